@@ -2,9 +2,11 @@ package baseapp
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"runtime/debug"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -17,7 +19,10 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-var mainHeaderKey = []byte("header")
+var (
+	mainHeaderKey          = []byte("header")
+	mainConsensusParamsKey = []byte("consensus_params")
+)
 
 // BaseApp - The ABCI application
 type BaseApp struct {
@@ -32,6 +37,9 @@ type BaseApp struct {
 	// Main (uncached) state
 	cms sdk.CommitMultiStore
 
+	// Key of the main store, which holds the last header.
+	mainKey sdk.StoreKey
+
 	// Unmarshal []byte into sdk.Tx
 	txDecoder sdk.TxDecoder
 
@@ -41,9 +49,25 @@ type BaseApp struct {
 	// Handle any kind of message.
 	router Router
 
+	// Codespaces of the app's modules.
+	codespacer *sdk.Codespacer
+
+	// Initialize state with genesis state.
+	initChainer sdk.InitChainer
+
+	// Per-block hooks, run against the deliver state.
+	beginBlocker sdk.BeginBlocker
+	endBlocker   sdk.EndBlocker
+
 	//--------------------
 	// Volatile
 
+	// Header of the last committed block.
+	lastHeader abci.Header
+
+	// Consensus params, kept in sync with main://<mainConsensusParamsKey>.
+	consensusParams sdk.ConsensusParams
+
 	// CheckTx state, a cache-wrap of `.cms`.
 	msCheck sdk.CacheMultiStore
 
@@ -59,41 +83,36 @@ type BaseApp struct {
 
 var _ abci.Application = &BaseApp{}
 
-func NewBaseApp(name string) *BaseApp {
+// NewBaseApp returns a BaseApp which persists its state to db.
+// Tests may pass dbm.NewMemDB().
+func NewBaseApp(name string, db dbm.DB) *BaseApp {
 	var baseapp = &BaseApp{
-		logger: makeDefaultLogger(),
-		name:   name,
-		db:     nil,
-		cms:    nil,
-		router: NewRouter(),
-	}
-	baseapp.initDB()
-	baseapp.initMultiStore()
-	return baseapp
-}
-
-// Create the underlying leveldb datastore which will
-// persist the Merkle tree inner & leaf nodes.
-func (app *BaseApp) initDB() {
-	db, err := dbm.NewGoLevelDB(app.name, "data")
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		logger:     makeDefaultLogger(),
+		name:       name,
+		db:         db,
+		cms:        store.NewCommitMultiStore(db),
+		router:     NewRouter(),
+		codespacer: sdk.NewCodespacer(),
 	}
-	app.db = db
-}
-
-func (app *BaseApp) initMultiStore() {
-	cms := store.NewCommitMultiStore(app.db)
-	app.cms = cms
+	return baseapp
 }
 
 func (app *BaseApp) Name() string {
 	return app.name
 }
 
+// MountStore mounts a store of type typ at key, in the app's db.
+// Each store gets its own prefix of the db, so that many IAVL stores
+// can share it.  Dbs first committed before the prefixes keep their
+// unprefixed layout.
 func (app *BaseApp) MountStore(key sdk.StoreKey, typ sdk.StoreType) {
-	app.cms.MountStoreWithDB(key, typ, app.db)
+	app.cms.MountStoreWithDB(key, typ, nil)
+}
+
+// RegisterCodespace reserves the codespace of a module's errors.
+// It panics if another module already registered it.
+func (app *BaseApp) RegisterCodespace(codespace sdk.CodespaceType) {
+	app.codespacer.RegisterCodespace(codespace)
 }
 
 func (app *BaseApp) SetTxDecoder(txDecoder sdk.TxDecoder) {
@@ -104,23 +123,35 @@ func (app *BaseApp) SetDefaultAnteHandler(ah sdk.AnteHandler) {
 	app.defaultAnteHandler = ah
 }
 
+func (app *BaseApp) SetInitChainer(initChainer sdk.InitChainer) {
+	app.initChainer = initChainer
+}
+
+func (app *BaseApp) SetBeginBlocker(beginBlocker sdk.BeginBlocker) {
+	app.beginBlocker = beginBlocker
+}
+
+func (app *BaseApp) SetEndBlocker(endBlocker sdk.EndBlocker) {
+	app.endBlocker = endBlocker
+}
+
 func (app *BaseApp) Router() Router {
 	return app.router
 }
 
-/* TODO consider:
-func (app *BaseApp) SetBeginBlocker(...) {}
-func (app *BaseApp) SetEndBlocker(...) {}
-func (app *BaseApp) SetInitStater(...) {}
-*/
-
 func (app *BaseApp) LoadLatestVersion(mainKey sdk.StoreKey) error {
-	app.cms.LoadLatestVersion()
+	err := app.cms.LoadLatestVersion()
+	if err != nil {
+		return err
+	}
 	return app.initFromStore(mainKey)
 }
 
 func (app *BaseApp) LoadVersion(version int64, mainKey sdk.StoreKey) error {
-	app.cms.LoadVersion(version)
+	err := app.cms.LoadVersion(version)
+	if err != nil {
+		return err
+	}
 	return app.initFromStore(mainKey)
 }
 
@@ -134,11 +165,16 @@ func (app *BaseApp) LastBlockHeight() int64 {
 	return app.cms.LastCommitID().Version
 }
 
+// The consensus params set by StoreConsensusParams.
+func (app *BaseApp) ConsensusParams() sdk.ConsensusParams {
+	return app.consensusParams
+}
+
 // Initializes the remaining logic from app.cms.
 func (app *BaseApp) initFromStore(mainKey sdk.StoreKey) error {
 	var lastCommitID = app.cms.LastCommitID()
 	var main = app.cms.GetKVStore(mainKey)
-	var header *abci.Header
+	var header abci.Header
 
 	// Main store should exist.
 	if main == nil {
@@ -152,7 +188,7 @@ func (app *BaseApp) initFromStore(mainKey sdk.StoreKey) error {
 			errStr := fmt.Sprintf("Version > 0 but missing key %s", mainHeaderKey)
 			return errors.New(errStr)
 		}
-		err := proto.Unmarshal(headerBytes, header)
+		err := proto.Unmarshal(headerBytes, &header)
 		if err != nil {
 			return errors.Wrap(err, "Failed to parse Header")
 		}
@@ -163,8 +199,20 @@ func (app *BaseApp) initFromStore(mainKey sdk.StoreKey) error {
 		}
 	}
 
+	// Consensus params are optional, and default to no limits.
+	var params sdk.ConsensusParams
+	if paramsBytes := main.Get(mainConsensusParamsKey); len(paramsBytes) != 0 {
+		err := json.Unmarshal(paramsBytes, &params)
+		if err != nil {
+			return errors.Wrap(err, "Failed to parse ConsensusParams")
+		}
+	}
+
 	// Set BaseApp state.
-	app.header = header
+	app.mainKey = mainKey
+	app.lastHeader = header
+	app.consensusParams = params
+	app.header = nil
 	app.msCheck = nil
 	app.msDeliver = nil
 	app.valUpdates = nil
@@ -193,24 +241,55 @@ func (app *BaseApp) SetOption(req abci.RequestSetOption) (res abci.ResponseSetOp
 }
 
 // Implements ABCI.
+// The initChainer runs against the deliver state of the first block,
+// so the genesis state gets committed along with it.
 func (app *BaseApp) InitChain(req abci.RequestInitChain) (res abci.ResponseInitChain) {
 	// TODO: Use req.Validators
+	if app.initChainer == nil {
+		return
+	}
+
+	// RequestInitChain has no genesis time, so ctx.BlockTime() is the
+	// Unix epoch.
+	app.msDeliver = app.cms.CacheMultiStore()
+	ctx := sdk.NewContext(app.msDeliver, abci.Header{}, false, nil)
+	ctx = ctx.WithConsensusParams(app.consensusParams)
+	res = app.initChainer(ctx, req)
 	return
 }
 
-// Implements ABCI.
-func (app *BaseApp) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
-	// TODO: See app/query.go
-	return
+// StoreConsensusParams sets the consensus params that are exposed to
+// handlers by ctx.ConsensusParams(), from the next context on.  It is
+// usually called by the InitChainer, from the genesis state.  The
+// params are stored in the main store of ctx, so they survive restarts.
+// It panics if params are invalid.
+func (app *BaseApp) StoreConsensusParams(ctx sdk.Context, params sdk.ConsensusParams) {
+	if err := params.ValidateBasic(); err != nil {
+		panic(err)
+	}
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		panic(err)
+	}
+	ctx.KVStore(app.mainKey).Set(mainConsensusParamsKey, paramsBytes)
+	app.consensusParams = params
 }
 
 // Implements ABCI.
 func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeginBlock) {
-	// NOTE: For consistency we should unset these upon EndBlock.
+	// NOTE: These get unset upon Commit.
 	app.header = &req.Header
-	app.msDeliver = app.cms.CacheMultiStore()
+	if app.msDeliver == nil {
+		// InitChain sets up msDeliver for the first block.
+		app.msDeliver = app.cms.CacheMultiStore()
+	}
 	app.msCheck = app.cms.CacheMultiStore()
 	app.valUpdates = nil
+
+	if app.beginBlocker != nil {
+		ctx := app.newContext(false, nil)
+		res = app.beginBlocker(ctx, req)
+	}
 	return
 }
 
@@ -223,14 +302,15 @@ func (app *BaseApp) CheckTx(txBytes []byte) (res abci.ResponseCheckTx) {
 	if err != nil {
 		result = err.Result()
 	} else {
-		result = app.runTx(true, txBytes, tx)
+		result = app.runTx(runTxModeCheck, txBytes, tx)
 	}
 
 	return abci.ResponseCheckTx{
-		Code:      uint32(result.Code),
+		Code:      uint32(result.ABCICode()),
 		Data:      result.Data,
 		Log:       result.Log,
 		GasWanted: result.GasWanted,
+		GasUsed:   result.GasUsed,
 		Fee: cmn.KI64Pair{
 			[]byte(result.FeeDenom),
 			result.FeeAmount,
@@ -249,12 +329,12 @@ func (app *BaseApp) DeliverTx(txBytes []byte) (res abci.ResponseDeliverTx) {
 	if err != nil {
 		result = err.Result()
 	} else {
-		result = app.runTx(false, txBytes, tx)
+		result = app.runTx(runTxModeDeliver, txBytes, tx)
 	}
 
 	// After-handler hooks.
 	if result.IsOK() {
-		app.valUpdates = append(app.valUpdates, result.ValidatorUpdates...)
+		app.valUpdates = mergeValUpdates(app.valUpdates, result.ValidatorUpdates)
 	} else {
 		// Even though the Code is not OK, there will be some side
 		// effects, like those caused by fee deductions or sequence
@@ -263,7 +343,7 @@ func (app *BaseApp) DeliverTx(txBytes []byte) (res abci.ResponseDeliverTx) {
 
 	// Tell the blockchain engine (i.e. Tendermint).
 	return abci.ResponseDeliverTx{
-		Code:      uint32(result.Code),
+		Code:      uint32(result.ABCICode()),
 		Data:      result.Data,
 		Log:       result.Log,
 		GasWanted: result.GasWanted,
@@ -272,55 +352,115 @@ func (app *BaseApp) DeliverTx(txBytes []byte) (res abci.ResponseDeliverTx) {
 	}
 }
 
+// runTxMode selects the state a tx runs against.
+type runTxMode uint8
+
+const (
+	// Check a tx against msCheck, without running its Msgs.
+	runTxModeCheck runTxMode = iota
+	// Deliver a tx against msDeliver.
+	runTxModeDeliver
+	// Run a tx against a cache of the last committed state,
+	// which is then discarded.
+	runTxModeSimulate
+	// Like runTxModeSimulate, but without verifying signatures.
+	runTxModeSimulateSkipSigs
+)
+
 // txBytes may be nil in some cases, for example, when tx is
 // coming from TestApp.  Also, in the future we may support
 // "internal" transactions.
-func (app *BaseApp) runTx(isCheckTx bool, txBytes []byte, tx sdk.Tx) (result sdk.Result) {
+func (app *BaseApp) runTx(mode runTxMode, txBytes []byte, tx sdk.Tx) (result sdk.Result) {
+
+	// Meter gas against the limit set by the Tx.
+	fee := tx.GetFee()
+	gasMeter := sdk.NewGasMeter(fee.Gas)
+
+	// Capture the log output of the Tx.
+	txLog := newTxLog(maxTxLogSize)
 
-	// Handle any panics.
+	// Handle any panics, and report the gas consumed and logs.
 	defer func() {
 		if r := recover(); r != nil {
-			log := fmt.Sprintf("Recovered: %v\nstack:\n%v", r, string(debug.Stack()))
-			result = sdk.ErrInternal(log).Result()
+			switch rType := r.(type) {
+			case sdk.ErrorOutOfGas:
+				log := fmt.Sprintf("Out of gas in location: %v", rType.Descriptor)
+				result = sdk.ErrOutOfGas(log).Result()
+			default:
+				// The stack is only logged with sdk.SetErrorDebug, like
+				// other traces.
+				log := fmt.Sprintf("Recovered: %v", r)
+				stack := fmt.Sprintf("stack:\n%s", debug.Stack())
+				result = sdk.ErrInternal(log).Trace(stack).Result()
+			}
 		}
+		result.GasWanted = fee.Gas
+		result.GasUsed = gasMeter.GasConsumed()
+		if len(fee.Amount) > 0 && fee.Amount[0].Amount.IsInt64() {
+			// NOTE: ABCI supports a fee of a single int64 denomination.
+			result.FeeAmount = fee.Amount[0].Amount.Int64()
+			result.FeeDenom = fee.Amount[0].Denom
+		}
+		result.Log = appendTxLog(result.Log, txLog)
 	}()
 
-	// Get the Msg.
-	var msg = tx.GetMsg()
-	if msg == nil {
-		return sdk.ErrInternal("Tx.GetMsg() returned nil").Result()
+	// Get the Msgs.
+	var msgs = tx.GetMsgs()
+	if len(msgs) == 0 {
+		return sdk.ErrInternal("Tx.GetMsgs() returned no Msgs").Result()
 	}
 
-	// Validate the Msg.
-	err := msg.ValidateBasic()
-	if err != nil {
-		return err.Result()
+	// Validate the Msgs.
+	for i, msg := range msgs {
+		if msg == nil {
+			return sdk.ErrInternal(fmt.Sprintf("Tx.GetMsgs()[%d] is nil", i)).Result()
+		}
+		err := msg.ValidateBasic()
+		if err != nil {
+			return err.Result()
+		}
 	}
 
-	// Construct a Context.
-	var ctx = app.newContext(isCheckTx, txBytes)
-
-	// TODO: override default ante handler w/ custom ante handler.
+	// Construct a Context on the MultiStore for mode.
+	var ms = app.getMultiStore(mode)
+	var ctx sdk.Context
+	switch mode {
+	case runTxModeSimulate, runTxModeSimulateSkipSigs:
+		ctx = app.newSimulateContext(ms, txBytes)
+		ctx = ctx.WithSkipSigCheck(mode == runTxModeSimulateSkipSigs)
+	default:
+		ctx = app.newContext(mode == runTxModeCheck, txBytes)
+	}
+	ctx = ctx.WithGasMeter(gasMeter)
+	ctx = ctx.WithLogger(txLog.Logger())
 
-	// Run the ante handler.
-	newCtx, result, abort := app.defaultAnteHandler(ctx, tx)
-	if isCheckTx || abort {
+	// Run the ante handler on a cache-wrap, so that its effects
+	// (e.g. fee deductions) are only kept if it doesn't abort.
+	anteHandler, err := app.msgsAnteHandler(msgs)
+	if err != nil {
+		return err.Result()
+	}
+	anteCache := ms.CacheMultiStore()
+	newCtx, result, abort := anteHandler(ctx.WithMultiStore(anteCache), tx)
+	if abort {
+		return result
+	}
+	anteCache.Write()
+	if mode == runTxModeCheck {
 		return result
 	}
 	if !newCtx.IsZero() {
 		ctx = newCtx
 	}
 
-	// CacheWrap app.msDeliver in case it fails.
-	msCache := app.getMultiStore(isCheckTx).CacheMultiStore()
+	// CacheWrap the tx's MultiStore in case it fails.
+	msCache := ms.CacheMultiStore()
 	ctx = ctx.WithMultiStore(msCache)
 
-	// Match and run route.
-	msgType := msg.Type()
-	handler := app.router.Route(msgType)
-	result = handler(ctx, msg)
+	// Run the Msgs.
+	result = app.runMsgs(ctx, msgs)
 
-	// If result was successful, write to app.msDeliver or app.msCheck.
+	// If all Msgs succeeded, write to the tx's MultiStore.
 	if result.IsOK() {
 		msCache.Write()
 	}
@@ -328,23 +468,91 @@ func (app *BaseApp) runTx(isCheckTx bool, txBytes []byte, tx sdk.Tx) (result sdk
 	return result
 }
 
+// Returns the AnteHandler for msgs: the AnteHandler of their route if
+// it has one, or else the default.  Msgs of a route with its own
+// AnteHandler cannot be mixed with Msgs of other routes.
+func (app *BaseApp) msgsAnteHandler(msgs []sdk.Msg) (sdk.AnteHandler, sdk.Error) {
+	anteRoute, anteHandler := app.router.AnteHandler(msgs[0].Type())
+	for _, msg := range msgs[1:] {
+		route, ah := app.router.AnteHandler(msg.Type())
+		if (ah != nil || anteHandler != nil) && route != anteRoute {
+			errMsg := fmt.Sprintf("Msgs of route %q cannot be mixed with Msgs of route %q", anteRoute, route)
+			return nil, sdk.ErrUnknownRequest(errMsg)
+		}
+	}
+	if anteHandler == nil {
+		anteHandler = app.defaultAnteHandler
+	}
+	return anteHandler, nil
+}
+
+// Runs msgs in order, stopping at the first failure.
+// The results of successful Msgs are combined.
+func (app *BaseApp) runMsgs(ctx sdk.Context, msgs []sdk.Msg) (result sdk.Result) {
+	var logs []string
+	for i, msg := range msgs {
+
+		// Match and run route.
+		msgType := msg.Type()
+		handler := app.router.Route(msgType)
+		if handler == nil {
+			return sdk.ErrUnknownRequest("Unrecognized Msg type: " + msgType).Result()
+		}
+		msgResult := handler(ctx, msg)
+
+		if !msgResult.IsOK() {
+			msgResult.Log = sdk.WithMsgIndex(msgResult.Log, i)
+			return msgResult
+		}
+
+		result.Data = append(result.Data, msgResult.Data...)
+		if msgResult.Log != "" {
+			logs = append(logs, msgResult.Log)
+		}
+		result.ValidatorUpdates = mergeValUpdates(result.ValidatorUpdates, msgResult.ValidatorUpdates)
+		result.Tags = append(result.Tags, msgResult.Tags...)
+	}
+	result.Log = strings.Join(logs, "\n")
+	return result
+}
+
 // Implements ABCI.
+// The endBlocker's validator updates take precedence over those
+// from the block's transactions.
 func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBlock) {
-	res.ValidatorUpdates = app.valUpdates
+	if app.endBlocker != nil {
+		ctx := app.newContext(false, nil)
+		res = app.endBlocker(ctx, req)
+	}
+	res.ValidatorUpdates = mergeValUpdates(app.valUpdates, res.ValidatorUpdates)
 	app.valUpdates = nil
-	app.header = nil
-	app.msDeliver = nil
-	app.msCheck = nil
 	return
 }
 
 // Implements ABCI.
 func (app *BaseApp) Commit() (res abci.ResponseCommit) {
+
+	// Write the latest Header to the main store,
+	// so that initFromStore can restore it.
+	headerBytes, err := proto.Marshal(app.header)
+	if err != nil {
+		panic(err)
+	}
+	main := app.msDeliver.GetKVStore(app.mainKey)
+	main.Set(mainHeaderKey, headerBytes)
+
 	app.msDeliver.Write()
 	commitID := app.cms.Commit()
 	app.logger.Debug("Commit synced",
 		"commit", commitID,
 	)
+
+	// Reset the volatile state for the next block.
+	app.lastHeader = *app.header
+	app.header = nil
+	app.msDeliver = nil
+	app.msCheck = nil
+
 	return abci.ResponseCommit{
 		Data: commitID.Hash,
 	}
@@ -353,16 +561,19 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 //----------------------------------------
 // Misc.
 
-func (app *BaseApp) getMultiStore(isCheckTx bool) sdk.MultiStore {
-	if isCheckTx {
+func (app *BaseApp) getMultiStore(mode runTxMode) sdk.MultiStore {
+	switch mode {
+	case runTxModeCheck:
 		return app.msCheck
-	} else {
+	case runTxModeDeliver:
 		return app.msDeliver
+	default:
+		return app.cms.CacheMultiStore()
 	}
 }
 
 // Return index of list with validator of same PubKey, or -1 if no match
-func pubKeyIndex(val *abci.Validator, list []*abci.Validator) int {
+func pubKeyIndex(val abci.Validator, list []abci.Validator) int {
 	for i, v := range list {
 		if bytes.Equal(val.PubKey, v.PubKey) {
 			return i
@@ -371,9 +582,20 @@ func pubKeyIndex(val *abci.Validator, list []*abci.Validator) int {
 	return -1
 }
 
+// Appends updates to valUpdates, where an update replaces any
+// earlier update with the same PubKey.
+func mergeValUpdates(valUpdates, updates []abci.Validator) []abci.Validator {
+	for _, val := range updates {
+		if i := pubKeyIndex(val, valUpdates); i >= 0 {
+			valUpdates[i] = val
+		} else {
+			valUpdates = append(valUpdates, val)
+		}
+	}
+	return valUpdates
+}
+
 // Make a simple default logger
-// TODO: Make log capturable for each transaction, and return it in
-// ResponseDeliverTx.Log and ResponseCheckTx.Log.
 func makeDefaultLogger() log.Logger {
 	return log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "sdk/app")
 }
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -26,17 +29,18 @@ const msgType = "testUpdatePowerTx"
 
 func (tx testUpdatePowerTx) Type() string                            { return msgType }
 func (tx testUpdatePowerTx) Get(key interface{}) (value interface{}) { return nil }
-func (tx testUpdatePowerTx) GetMsg() sdk.Msg                         { return tx }
+func (tx testUpdatePowerTx) GetMsgs() []sdk.Msg                      { return []sdk.Msg{tx} }
 func (tx testUpdatePowerTx) GetSignBytes() []byte                    { return nil }
 func (tx testUpdatePowerTx) ValidateBasic() sdk.Error                { return nil }
-func (tx testUpdatePowerTx) GetSigners() []crypto.Address            { return nil }
-func (tx testUpdatePowerTx) GetFeePayer() crypto.Address             { return nil }
+func (tx testUpdatePowerTx) GetSigners() []sdk.Address               { return nil }
+func (tx testUpdatePowerTx) GetFeePayer() sdk.Address                { return nil }
 func (tx testUpdatePowerTx) GetSignatures() []sdk.StdSignature       { return nil }
+func (tx testUpdatePowerTx) GetFee() sdk.StdFee                      { return sdk.NewStdFee(math.MaxInt64) }
 
 func TestBasic(t *testing.T) {
 
 	// Create app.
-	app := NewBaseApp(t.Name())
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
 	storeKeys := createMounts(app.cms)
 	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
 		var ttx testUpdatePowerTx
@@ -112,6 +116,630 @@ func TestBasic(t *testing.T) {
 	assert.Equal(t, len(valUpdates), 0, "Some validator updates were unexpected")
 }
 
+func TestInitChainer(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+
+	// The initChainer writes to the main store.
+	key, value := []byte("hello"), []byte("goodbye")
+	app.SetInitChainer(func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+		store := ctx.KVStore(storeKeys["main"])
+		store.Set(key, value)
+		return abci.ResponseInitChain{}
+	})
+
+	err := app.LoadLatestVersion(storeKeys["main"])
+	assert.Nil(t, err)
+
+	// Nothing is committed until the first block is.
+	app.InitChain(abci.RequestInitChain{})
+	query := abci.RequestQuery{
+		Path: "/store/main/key",
+		Data: key,
+	}
+	res := app.Query(query)
+	assert.Nil(t, res.Value)
+
+	// The genesis state is committed with the first block.
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	app.EndBlock(abci.RequestEndBlock{})
+	app.Commit()
+	res = app.Query(query)
+	assert.Equal(t, value, res.Value)
+}
+
+func TestBeginEndBlockers(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	mainKey := storeKeys["main"]
+
+	// The beginBlocker records the absent validators of the block.
+	absentKey := []byte("absent")
+	app.SetBeginBlocker(func(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+		store := ctx.KVStore(mainKey)
+		store.Set(absentKey, toJSON(req.AbsentValidators))
+		return abci.ResponseBeginBlock{}
+	})
+
+	// The endBlocker overrides the update made by the tx.
+	valTx := makeVal(secret(0))
+	valEnd := copyVal(valTx)
+	valEnd.Power = valTx.Power + 1
+	valNew := makeVal(secret(1))
+	app.SetEndBlocker(func(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
+		return abci.ResponseEndBlock{
+			ValidatorUpdates: []abci.Validator{valEnd, valNew},
+		}
+	})
+
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testUpdatePowerTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		return sdk.Result{
+			ValidatorUpdates: []abci.Validator{valTx},
+		}
+	})
+
+	err := app.LoadLatestVersion(mainKey)
+	assert.Nil(t, err)
+
+	// Run a block.
+	absent := []int32{0, 2}
+	app.BeginBlock(abci.RequestBeginBlock{
+		Header:           abci.Header{Height: 1},
+		AbsentValidators: absent,
+	})
+	res := app.DeliverTx(toJSON(testUpdatePowerTx{}))
+	assert.True(t, res.IsOK(), "%#v\nABCI log: %s", res, res.Log)
+	endRes := app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	// Validator updates are merged by PubKey.
+	assert.Equal(t, []abci.Validator{valEnd, valNew}, endRes.ValidatorUpdates)
+
+	// The beginBlocker wrote to the deliver state.
+	qres := app.Query(abci.RequestQuery{
+		Path: "/store/main/key",
+		Data: absentKey,
+	})
+	assert.Equal(t, toJSON(absent), qres.Value)
+}
+
+// Creates a BaseApp whose state lives in dbs, so that it can be
+// restarted by calling newRestartApp again with the same dbs.
+func newRestartApp(t *testing.T, name string, dbs []dbm.DB) (*BaseApp, sdk.StoreKey) {
+	app := NewBaseApp(name, dbs[0])
+	keyMain := sdk.NewKVStoreKey("main")
+	keyXtra := sdk.NewKVStoreKey("xtra")
+	app.cms.MountStoreWithDB(keyMain, sdk.StoreTypeIAVL, dbs[1])
+	app.cms.MountStoreWithDB(keyXtra, sdk.StoreTypeIAVL, dbs[2])
+
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testUpdatePowerTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		tx := msg.(testUpdatePowerTx)
+		ctx.KVStore(keyMain).Set(tx.Addr, toJSON(tx.NewPower))
+		ctx.KVStore(keyXtra).Set(tx.Addr, toJSON(ctx.BlockHeight()))
+		return sdk.Result{}
+	})
+
+	err := app.LoadLatestVersion(keyMain)
+	assert.Nil(t, err)
+	return app, keyMain
+}
+
+// Runs a block with a single tx and returns the app hash.
+func runBlock(t *testing.T, app *BaseApp, height int64) []byte {
+	app.BeginBlock(abci.RequestBeginBlock{
+		Header: abci.Header{ChainID: "test-chain", Height: height},
+	})
+	tx := testUpdatePowerTx{
+		Addr:     []byte(fmt.Sprintf("addr%d", height)),
+		NewPower: height * 10,
+	}
+	res := app.DeliverTx(toJSON(tx))
+	assert.True(t, res.IsOK(), "%#v\nABCI log: %s", res, res.Log)
+	app.EndBlock(abci.RequestEndBlock{Height: height})
+	return app.Commit().Data
+}
+
+func TestRestart(t *testing.T) {
+	newDBs := func() []dbm.DB {
+		return []dbm.DB{dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB()}
+	}
+	var numBlocks, restartAt int64 = 5, 3
+
+	// An app that runs without interruption.
+	control, _ := newRestartApp(t, t.Name(), newDBs())
+	var hashes [][]byte
+	for h := int64(1); h <= numBlocks; h++ {
+		hashes = append(hashes, runBlock(t, control, h))
+	}
+
+	// An app that restarts after a few blocks.
+	dbs := newDBs()
+	app, _ := newRestartApp(t, t.Name(), dbs)
+	for h := int64(1); h <= restartAt; h++ {
+		assert.Equal(t, hashes[h-1], runBlock(t, app, h))
+	}
+	app, keyMain := newRestartApp(t, t.Name(), dbs)
+
+	// The last committed state is restored.
+	assert.Equal(t, restartAt, app.LastBlockHeight())
+	assert.Equal(t, hashes[restartAt-1], app.LastCommitID().Hash)
+	assert.Equal(t, "test-chain", app.lastHeader.ChainID)
+	assert.Equal(t, restartAt, app.lastHeader.Height)
+	main := app.cms.GetKVStore(keyMain)
+	assert.Equal(t, toJSON(restartAt*10), main.Get([]byte(fmt.Sprintf("addr%d", restartAt))))
+
+	// The restarted app continues to produce identical app hashes.
+	for h := restartAt + 1; h <= numBlocks; h++ {
+		assert.Equal(t, hashes[h-1], runBlock(t, app, h))
+	}
+}
+
+func TestBlockContext(t *testing.T) {
+	dbs := []dbm.DB{dbm.NewMemDB(), dbm.NewMemDB(), dbm.NewMemDB()}
+	app, _ := newRestartApp(t, t.Name(), dbs)
+
+	// The initChainer sets the consensus params from genesis.
+	params := sdk.ConsensusParams{MaxBlockGas: 1000000, MaxBlockBytes: 22020096}
+	app.SetInitChainer(func(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+		assert.Equal(t, time.Unix(0, 0).UTC(), ctx.BlockTime())
+		assert.Panics(t, func() {
+			app.StoreConsensusParams(ctx, sdk.ConsensusParams{MaxBlockGas: -1})
+		})
+		app.StoreConsensusParams(ctx, params)
+		return abci.ResponseInitChain{}
+	})
+	app.InitChain(abci.RequestInitChain{})
+
+	// Handlers see the block time and the consensus params.
+	blockTime := time.Date(2018, 4, 1, 12, 30, 0, 0, time.UTC)
+	app.BeginBlock(abci.RequestBeginBlock{
+		Header: abci.Header{ChainID: "test-chain", Height: 1, Time: blockTime.Unix()},
+	})
+	ctx := app.newContext(false, nil)
+	assert.Equal(t, blockTime, ctx.BlockTime())
+	assert.Equal(t, params, ctx.ConsensusParams())
+	app.EndBlock(abci.RequestEndBlock{Height: 1})
+	app.Commit()
+
+	// The consensus params are restored on restart.
+	app, _ = newRestartApp(t, t.Name(), dbs)
+	ctx = app.newSimulateContext(app.cms.CacheMultiStore(), nil)
+	assert.Equal(t, params, ctx.ConsensusParams())
+	assert.Equal(t, blockTime, ctx.BlockTime())
+}
+
+// A mock transaction with a gas limit.
+type testGasTx struct {
+	testUpdatePowerTx
+	Gas int64
+}
+
+func (tx testGasTx) GetMsgs() []sdk.Msg { return []sdk.Msg{tx} }
+func (tx testGasTx) GetFee() sdk.StdFee { return sdk.NewStdFee(tx.Gas) }
+
+func TestOutOfGas(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	mainKey := storeKeys["main"]
+
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testGasTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+
+	// The handler writes a 100 byte value at a 3 byte key, which costs
+	// 1040 gas.
+	key := []byte("key")
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		store := ctx.KVStore(mainKey)
+		store.Set(key, make([]byte, 100))
+		return sdk.Result{}
+	})
+
+	err := app.LoadLatestVersion(mainKey)
+	assert.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{})
+
+	// Not enough gas.
+	res := app.DeliverTx(toJSON(testGasTx{Gas: 1000}))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeOutOfGas), sdk.ABCICodeType(res.Code), res.Log)
+	assert.Equal(t, int64(1000), res.GasWanted)
+	assert.Equal(t, int64(1000), res.GasUsed)
+	store := app.msDeliver.GetKVStore(mainKey)
+	assert.Nil(t, store.Get(key), "Expected write to be aborted")
+
+	// Enough gas.
+	res = app.DeliverTx(toJSON(testGasTx{Gas: 2000}))
+	assert.True(t, res.IsOK(), "%#v\nABCI log: %s", res, res.Log)
+	assert.Equal(t, int64(2000), res.GasWanted)
+	assert.Equal(t, int64(1040), res.GasUsed)
+	assert.Equal(t, make([]byte, 100), store.Get(key))
+}
+
+// newTxLoggerApp returns an app whose handler logs a line per unit
+// of power.
+func newTxLoggerApp(t *testing.T) *BaseApp {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testUpdatePowerTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		logger := ctx.Logger().With("module", "test")
+		for i := int64(0); i < msg.(testUpdatePowerTx).NewPower; i++ {
+			logger.Info("Updating power", "i", i)
+		}
+		return sdk.Result{Log: "done"}
+	})
+
+	err := app.LoadLatestVersion(storeKeys["main"])
+	assert.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{})
+	return app
+}
+
+func TestTxLogger(t *testing.T) {
+	app := newTxLoggerApp(t)
+
+	// The handler's output is appended to the result log.
+	res := app.DeliverTx(toJSON(testUpdatePowerTx{NewPower: 1}))
+	assert.True(t, res.IsOK(), "%#v\nABCI log: %s", res, res.Log)
+	assert.Equal(t, "done\nI Updating power module=test i=0\n", res.Log)
+
+	// Each tx has its own log.
+	res = app.DeliverTx(toJSON(testUpdatePowerTx{NewPower: 0}))
+	assert.Equal(t, "done", res.Log)
+
+	// The captured output is bounded.
+	res = app.DeliverTx(toJSON(testUpdatePowerTx{NewPower: 10000}))
+	assert.True(t, res.IsOK(), "%#v\nABCI log: %s", res, res.Log)
+	assert.True(t, strings.HasSuffix(res.Log, txLogTruncated), res.Log)
+	assert.Equal(t, len("done\n")+maxTxLogSize+len(txLogTruncated), len(res.Log))
+}
+
+func TestTxLoggerDeterministic(t *testing.T) {
+	txBytes := toJSON(testUpdatePowerTx{NewPower: 3})
+
+	// The same tx logs the same on another node, later.
+	res1 := newTxLoggerApp(t).DeliverTx(txBytes)
+	time.Sleep(10 * time.Millisecond)
+	res2 := newTxLoggerApp(t).DeliverTx(txBytes)
+	assert.True(t, res1.IsOK(), res1.Log)
+	assert.Equal(t, res1.Log, res2.Log)
+}
+
+// A mock transaction with several Msgs.
+type testMultiTx struct {
+	Msgs []testUpdatePowerTx
+}
+
+func (tx testMultiTx) GetMsgs() []sdk.Msg {
+	msgs := make([]sdk.Msg, len(tx.Msgs))
+	for i, msg := range tx.Msgs {
+		msgs[i] = msg
+	}
+	return msgs
+}
+func (tx testMultiTx) GetSigners() []sdk.Address         { return nil }
+func (tx testMultiTx) GetFeePayer() sdk.Address          { return nil }
+func (tx testMultiTx) GetSignatures() []sdk.StdSignature { return nil }
+func (tx testMultiTx) GetFee() sdk.StdFee                { return sdk.NewStdFee(math.MaxInt64) }
+
+func TestMultiMsgTx(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	mainKey := storeKeys["main"]
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testMultiTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+
+	// The handler stores the power, and rejects negative powers.
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		tx := msg.(testUpdatePowerTx)
+		if tx.NewPower < 0 {
+			return sdk.ErrUnknownRequest("negative power").Result()
+		}
+		ctx.KVStore(mainKey).Set(tx.Addr, toJSON(tx.NewPower))
+		return sdk.Result{
+			Data: tx.Addr,
+			Log:  fmt.Sprintf("set %s", tx.Addr),
+			Tags: []cmn.KVPair{{tx.Addr, toJSON(tx.NewPower)}},
+		}
+	})
+
+	err := app.LoadLatestVersion(mainKey)
+	assert.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{})
+	store := app.msDeliver.GetKVStore(mainKey)
+
+	// The results of all Msgs are combined.
+	res := app.DeliverTx(toJSON(testMultiTx{[]testUpdatePowerTx{
+		{Addr: []byte("a"), NewPower: 1},
+		{Addr: []byte("b"), NewPower: 2},
+	}}))
+	assert.True(t, res.IsOK(), "%#v\nABCI log: %s", res, res.Log)
+	assert.Equal(t, []byte("ab"), res.Data)
+	assert.Equal(t, "set a\nset b", res.Log)
+	assert.Equal(t, []cmn.KVPair{{[]byte("a"), toJSON(1)}, {[]byte("b"), toJSON(2)}}, res.Tags)
+	assert.Equal(t, toJSON(1), store.Get([]byte("a")))
+	assert.Equal(t, toJSON(2), store.Get([]byte("b")))
+
+	// A failing Msg rolls back the whole tx.
+	res = app.DeliverTx(toJSON(testMultiTx{[]testUpdatePowerTx{
+		{Addr: []byte("a"), NewPower: 3},
+		{Addr: []byte("c"), NewPower: -1},
+	}}))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code), res.Log)
+	assert.Equal(t, toJSON(1), store.Get([]byte("a")))
+	assert.Nil(t, store.Get([]byte("c")))
+}
+
+func TestUnknownRoute(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testUpdatePowerTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+
+	err := app.LoadLatestVersion(storeKeys["main"])
+	assert.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{})
+
+	// No route for msgType.
+	res := app.DeliverTx(toJSON(testUpdatePowerTx{}))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code), res.Log)
+}
+
+func TestCodespaces(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	const testCodespace sdk.CodespaceType = 2
+
+	// Codespaces can only be registered once.
+	app.RegisterCodespace(testCodespace)
+	assert.Panics(t, func() { app.RegisterCodespace(testCodespace) })
+	assert.Panics(t, func() { app.RegisterCodespace(sdk.CodespaceRoot) })
+	assert.Panics(t, func() { app.RegisterCodespace(0) })
+
+	storeKeys := createMounts(app.cms)
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testTypedTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute("test", func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		return sdk.NewError(testCodespace, 7, "").Result()
+	})
+	err := app.LoadLatestVersion(storeKeys["main"])
+	assert.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{})
+
+	// The codespace is part of the ABCI code and log.
+	res := app.DeliverTx(toJSON(testTypedTx{[]string{"test"}}))
+	code := sdk.ABCICodeType(res.Code)
+	assert.Equal(t, sdk.ToABCICode(testCodespace, 7), code)
+	assert.Equal(t, testCodespace, code.Codespace())
+	assert.Equal(t, sdk.CodeType(7), code.Code())
+	errLog, err := sdk.ParseErrorLog(res.Log)
+	if assert.Nil(t, err, res.Log) {
+		assert.Equal(t, testCodespace, errLog.Codespace)
+		assert.Equal(t, sdk.CodeType(7), errLog.Code)
+		if assert.NotNil(t, errLog.MsgIndex) {
+			assert.Equal(t, 0, *errLog.MsgIndex)
+		}
+	}
+
+	// Root errors are in CodespaceRoot.
+	res = app.DeliverTx(toJSON(testTypedTx{[]string{"unknown"}}))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code))
+}
+
+func TestPanicLog(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testTypedTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+	app.Router().AddRoute("test", func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		panic("handler bug")
+	})
+	err := app.LoadLatestVersion(storeKeys["main"])
+	assert.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{})
+
+	// The log of a panic has no stack, which differs between builds.
+	res := app.DeliverTx(toJSON(testTypedTx{[]string{"test"}}))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInternal), sdk.ABCICodeType(res.Code), res.Log)
+	assert.NotContains(t, res.Log, "stack:")
+	errLog, err := sdk.ParseErrorLog(res.Log)
+	if assert.Nil(t, err, res.Log) {
+		assert.Equal(t, "Recovered: handler bug", errLog.Message)
+		assert.Empty(t, errLog.Trace)
+	}
+
+	// Unless error debugging is on.
+	sdk.SetErrorDebug(true)
+	defer sdk.SetErrorDebug(false)
+	res = app.DeliverTx(toJSON(testTypedTx{[]string{"test"}}))
+	assert.Contains(t, res.Log, "stack:")
+}
+
+// A mock Msg with a given type.
+type testTypedMsg struct {
+	testUpdatePowerTx
+	MsgType string
+}
+
+func (msg testTypedMsg) Type() string { return msg.MsgType }
+
+// A mock transaction with Msgs of the given types.
+type testTypedTx struct {
+	MsgTypes []string
+}
+
+func (tx testTypedTx) GetMsgs() []sdk.Msg {
+	msgs := make([]sdk.Msg, len(tx.MsgTypes))
+	for i, msgType := range tx.MsgTypes {
+		msgs[i] = testTypedMsg{MsgType: msgType}
+	}
+	return msgs
+}
+func (tx testTypedTx) GetSigners() []sdk.Address         { return nil }
+func (tx testTypedTx) GetFeePayer() sdk.Address          { return nil }
+func (tx testTypedTx) GetSignatures() []sdk.StdSignature { return nil }
+func (tx testTypedTx) GetFee() sdk.StdFee                { return sdk.NewStdFee(math.MaxInt64) }
+
+func TestRouteAnteHandlers(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testTypedTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+
+	// Each ante handler appends its name to the ante handlers that ran.
+	type anteKey struct{}
+	namedAnteHandler := func(name string) sdk.AnteHandler {
+		return func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) {
+			ran, _ := ctx.Value(anteKey{}).(string)
+			return ctx.WithValue(anteKey{}, ran+name+";"), res, false
+		}
+	}
+	handler := func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		return sdk.Result{Log: ctx.Value(anteKey{}).(string)}
+	}
+	app.SetDefaultAnteHandler(namedAnteHandler("default"))
+	app.Router().AddRoute("bank", handler)
+	app.Router().AddRoute("ibc", handler)
+	app.Router().AddRouteWithAnteHandler("gov", namedAnteHandler("gov"), handler)
+	app.Router().AddRouteWithAnteHandler("relay",
+		sdk.ChainAnteHandlers(namedAnteHandler("default"), namedAnteHandler("relay")),
+		handler)
+
+	err := app.LoadLatestVersion(storeKeys["main"])
+	assert.Nil(t, err)
+	app.BeginBlock(abci.RequestBeginBlock{})
+
+	cases := []struct {
+		msgTypes []string
+		log      string
+	}{
+		{[]string{"bank/send"}, "default;"},
+		{[]string{"bank/send", "ibc/packet"}, "default;\ndefault;"},
+		{[]string{"gov/vote"}, "gov;"},
+		{[]string{"gov/vote", "gov/propose"}, "gov;\ngov;"},
+		{[]string{"relay"}, "default;relay;"},
+	}
+	for _, tc := range cases {
+		res := app.DeliverTx(toJSON(testTypedTx{tc.msgTypes}))
+		assert.True(t, res.IsOK(), "%v: %s", tc.msgTypes, res.Log)
+		assert.Equal(t, tc.log, res.Log, "%v", tc.msgTypes)
+	}
+
+	// Msgs of a route with its own ante handler can't be mixed.
+	for _, msgTypes := range [][]string{
+		{"gov/vote", "bank/send"},
+		{"bank/send", "gov/vote"},
+		{"gov/vote", "relay"},
+	} {
+		res := app.DeliverTx(toJSON(testTypedTx{msgTypes}))
+		assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code), "%v: %s", msgTypes, res.Log)
+	}
+}
+
+func TestSimulateTx(t *testing.T) {
+	app := NewBaseApp(t.Name(), dbm.NewMemDB())
+	storeKeys := createMounts(app.cms)
+	mainKey := storeKeys["main"]
+	app.SetTxDecoder(func(txBytes []byte) (sdk.Tx, sdk.Error) {
+		var ttx testGasTx
+		fromJSON(txBytes, &ttx)
+		return ttx, nil
+	})
+	app.SetDefaultAnteHandler(func(ctx sdk.Context, tx sdk.Tx) (newCtx sdk.Context, res sdk.Result, abort bool) { return })
+
+	// The handler stores the power, and rejects negative powers.
+	app.Router().AddRoute(msgType, func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		tx := msg.(testGasTx)
+		if tx.NewPower < 0 {
+			return sdk.ErrUnknownRequest("negative power").Result()
+		}
+		ctx.KVStore(mainKey).Set(tx.Addr, toJSON(tx.NewPower))
+		return sdk.Result{Tags: []cmn.KVPair{{tx.Addr, nil}}}
+	})
+
+	err := app.LoadLatestVersion(mainKey)
+	assert.Nil(t, err)
+
+	simulate := func(tx testGasTx) (sdk.Result, abci.ResponseQuery) {
+		res := app.Query(abci.RequestQuery{
+			Path: "/app/simulate",
+			Data: toJSON(tx),
+		})
+		var result sdk.Result
+		fromJSON(res.Value, &result)
+		return result, res
+	}
+	addr := []byte("addr")
+
+	// Simulate a successful tx.
+	tx := testGasTx{testUpdatePowerTx{Addr: addr, NewPower: 7}, 10000}
+	result, res := simulate(tx)
+	assert.True(t, res.IsOK(), res.Log)
+	assert.Equal(t, sdk.ABCICodeOK, result.ABCICode())
+	assert.Equal(t, int64(10000), result.GasWanted)
+	assert.True(t, result.GasUsed > 0)
+	assert.Equal(t, []cmn.KVPair{{addr, nil}}, result.Tags)
+
+	// Simulate a failing tx.
+	failTx := testGasTx{testUpdatePowerTx{Addr: addr, NewPower: -1}, 10000}
+	failResult, res := simulate(failTx)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code), res.Log)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), failResult.ABCICode())
+
+	// Nothing was written.
+	app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	store := app.msDeliver.GetKVStore(mainKey)
+	assert.Nil(t, store.Get(addr))
+
+	// Delivering the tx uses the simulated amount of gas.
+	deliverRes := app.DeliverTx(toJSON(tx))
+	assert.True(t, deliverRes.IsOK(), deliverRes.Log)
+	assert.Equal(t, result.GasUsed, deliverRes.GasUsed)
+	assert.Equal(t, toJSON(int64(7)), store.Get(addr))
+
+	// Unknown paths are rejected.
+	res = app.Query(abci.RequestQuery{Path: "/app/unknown"})
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnknownRequest), sdk.ABCICodeType(res.Code), res.Log)
+}
+
 //----------------------------------------
 
 func randPower() int64 {
@@ -26,5 +26,37 @@ func (app *BaseApp) newContext(isCheckTx bool, txBytes []byte) sdk.Context {
 		isCheckTx,
 		txBytes,
 	)
+	ctx = ctx.WithConsensusParams(app.consensusParams)
+	ctx = ctx.WithLogger(app.logger)
+	return ctx
+}
+
+// Returns a new Context for simulating a tx against ms,
+// as if in the block after the last committed one.
+func (app *BaseApp) newSimulateContext(ms sdk.MultiStore, txBytes []byte) sdk.Context {
+	header := app.lastHeader
+	header.Height++
+	var ctx = sdk.NewContext(
+		ms,
+		header,
+		false,
+		txBytes,
+	)
+	ctx = ctx.WithConsensusParams(app.consensusParams)
+	ctx = ctx.WithLogger(app.logger)
+	return ctx
+}
+
+// LastCommitContext returns a new Context over a cache-wrap of the last
+// committed state, e.g. to inspect it.  Writes to it are discarded.
+func (app *BaseApp) LastCommitContext() sdk.Context {
+	var ctx = sdk.NewContext(
+		app.cms.CacheMultiStore(),
+		app.lastHeader,
+		false,
+		nil,
+	)
+	ctx = ctx.WithConsensusParams(app.consensusParams)
+	ctx = ctx.WithLogger(app.logger)
 	return ctx
 }
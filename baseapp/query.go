@@ -1,54 +1,74 @@
 package baseapp
 
-/*
-	XXX Make this work with MultiStore.
-	XXX It will require some interfaces updates in store/types.go.
-
-	if len(reqQuery.Data) == 0 {
-		resQuery.Log = "Query cannot be zero length"
-		resQuery.Code = abci.CodeType_EncodingError
-		return
-	}
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
 
-	// set the query response height to current
-	tree := app.state.Committed()
-
-	height := reqQuery.Height
-	if height == 0 {
-		// TODO: once the rpc actually passes in non-zero
-		// heights we can use to query right after a tx
-		// we must retrun most recent, even if apphash
-		// is not yet in the blockchain
-
-		withProof := app.CommittedHeight() - 1
-		if tree.Tree.VersionExists(withProof) {
-			height = withProof
-		} else {
-			height = app.CommittedHeight()
-		}
+	abci "github.com/tendermint/abci/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Implements ABCI.
+//
+// Supported paths:
+//
+//	/store/<storeName>/key  - value of req.Data in the named KVStore,
+//	                          with a proof if req.Prove is set.
+//	/app/simulate           - runs the tx in req.Data against the last
+//	                          committed state without committing, and
+//	                          returns the JSON encoded sdk.Result.
+//	/app/simulate/skipsigs  - like /app/simulate, but signatures are
+//	                          not verified.
+func (app *BaseApp) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
+	paths := strings.SplitN(strings.TrimPrefix(req.Path, "/"), "/", 2)
+	if len(paths) == 2 && paths[0] == "app" {
+		return app.queryApp(paths[1], req)
 	}
-	resQuery.Height = height
-
-	switch reqQuery.Path {
-	case "/store", "/key": // Get by key
-		key := reqQuery.Data // Data holds the key bytes
-		resQuery.Key = key
-		if reqQuery.Prove {
-			value, proof, err := tree.GetVersionedWithProof(key, height)
-			if err != nil {
-				resQuery.Log = err.Error()
-				break
-			}
-			resQuery.Value = value
-			resQuery.Proof = proof.Bytes()
-		} else {
-			value := tree.Get(key)
-			resQuery.Value = value
+	if len(paths) == 2 && paths[0] == "store" {
+		queryable, ok := app.cms.(sdk.Queryable)
+		if !ok {
+			msg := "multistore doesn't support queries"
+			return sdk.ErrUnknownRequest(msg).QueryResult()
 		}
+		req.Path = "/" + paths[1]
+		return queryable.Query(req)
+	}
+
+	msg := fmt.Sprintf("Unexpected Query path: %v", req.Path)
+	return sdk.ErrUnknownRequest(msg).QueryResult()
+}
 
+func (app *BaseApp) queryApp(path string, req abci.RequestQuery) (res abci.ResponseQuery) {
+	var mode runTxMode
+	switch path {
+	case "simulate":
+		mode = runTxModeSimulate
+	case "simulate/skipsigs":
+		mode = runTxModeSimulateSkipSigs
 	default:
-		resQuery.Code = abci.CodeType_UnknownRequest
-		resQuery.Log = cmn.Fmt("Unexpected Query path: %v", reqQuery.Path)
+		msg := fmt.Sprintf("Unexpected Query path: %v", req.Path)
+		return sdk.ErrUnknownRequest(msg).QueryResult()
+	}
+
+	// Decode and simulate the Tx.
+	var result sdk.Result
+	var tx, err = app.txDecoder(req.Data)
+	if err != nil {
+		result = err.Result()
+	} else {
+		result = app.runTx(mode, req.Data, tx)
+	}
+
+	value, jsonErr := json.Marshal(result)
+	if jsonErr != nil {
+		return sdk.ErrInternal(jsonErr.Error()).QueryResult()
+	}
+	return abci.ResponseQuery{
+		Code:   uint32(result.ABCICode()),
+		Log:    result.Log,
+		Value:  value,
+		Height: app.LastBlockHeight(),
 	}
-	return
-*/
+}
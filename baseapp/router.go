@@ -1,46 +1,88 @@
 package baseapp
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// Router routes Msgs to Handlers by Msg.Type().
+//
+// A route is a path of alphanumeric segments separated by "/", such
+// as "bank" or "bank/send".  A Msg is routed to the longest route
+// that is a prefix of its type, segment by segment, so the route
+// "bank" receives all "bank/*" Msgs that have no route of their own.
 type Router interface {
 	AddRoute(r string, h sdk.Handler)
+
+	// AddRouteWithAnteHandler is like AddRoute, but Msgs routed to r
+	// are run through ah instead of the BaseApp's default AnteHandler.
+	// Use sdk.ChainAnteHandlers to extend the default instead.
+	AddRouteWithAnteHandler(r string, ah sdk.AnteHandler, h sdk.Handler)
+
 	Route(path string) (h sdk.Handler)
+
+	// AnteHandler returns the route matching path, and its AnteHandler
+	// if it was added with one.  The route is "" if none matches.
+	AnteHandler(path string) (r string, ah sdk.AnteHandler)
 }
 
 type route struct {
-	r string
-	h sdk.Handler
+	r  string
+	ah sdk.AnteHandler
+	h  sdk.Handler
 }
 
 type router struct {
-	routes []route
+	routes map[string]route
 }
 
 func NewRouter() *router {
 	return &router{
-		routes: make([]route, 0),
+		routes: make(map[string]route),
 	}
 }
 
-var isAlpha = regexp.MustCompile(`^[a-zA-Z]+$`).MatchString
+var isRoute = regexp.MustCompile(`^[a-zA-Z0-9]+(/[a-zA-Z0-9]+)*$`).MatchString
 
+// Panics if r is malformed or already registered.
 func (rtr *router) AddRoute(r string, h sdk.Handler) {
-	if !isAlpha(r) {
-		panic("route expressions can only contain alphanumeric characters")
+	rtr.AddRouteWithAnteHandler(r, nil, h)
+}
+
+// Panics if r is malformed or already registered.
+func (rtr *router) AddRouteWithAnteHandler(r string, ah sdk.AnteHandler, h sdk.Handler) {
+	if !isRoute(r) {
+		panic(fmt.Sprintf("route %q must be alphanumeric segments separated by '/'", r))
+	}
+	if _, ok := rtr.routes[r]; ok {
+		panic(fmt.Sprintf("route %q has already been registered", r))
 	}
-	rtr.routes = append(rtr.routes, route{r, h})
+	rtr.routes[r] = route{r, ah, h}
 }
 
-// TODO handle expressive matches.
+// Returns nil if no route matches path.
 func (rtr *router) Route(path string) (h sdk.Handler) {
-	for _, route := range rtr.routes {
-		if route.r == path {
-			return route.h
+	return rtr.match(path).h
+}
+
+func (rtr *router) AnteHandler(path string) (r string, ah sdk.AnteHandler) {
+	rt := rtr.match(path)
+	return rt.r, rt.ah
+}
+
+// Returns the longest route that prefixes path, or the zero route.
+func (rtr *router) match(path string) route {
+	for {
+		if rt, ok := rtr.routes[path]; ok {
+			return rt
+		}
+		i := strings.LastIndex(path, "/")
+		if i < 0 {
+			return route{}
 		}
+		path = path[:i]
 	}
-	return nil
 }
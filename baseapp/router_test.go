@@ -0,0 +1,84 @@
+package baseapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Returns a Handler whose Result.Log is name.
+func namedHandler(name string) sdk.Handler {
+	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
+		return sdk.Result{Log: name}
+	}
+}
+
+func routedTo(rtr Router, path string) string {
+	h := rtr.Route(path)
+	if h == nil {
+		return ""
+	}
+	return h(sdk.Context{}, nil).Log
+}
+
+func TestRouter(t *testing.T) {
+	rtr := NewRouter()
+	rtr.AddRoute("bank", namedHandler("bank"))
+	rtr.AddRoute("bank/issue", namedHandler("bank/issue"))
+	rtr.AddRoute("ibc", namedHandler("ibc"))
+
+	cases := []struct {
+		path  string
+		route string
+	}{
+		{"bank", "bank"},
+		{"bank/send", "bank"},
+		{"bank/send/multi", "bank"},
+		{"bank/issue", "bank/issue"},
+		{"bank/issue/more", "bank/issue"},
+		{"ibc/packet", "ibc"},
+		{"banks", ""},
+		{"bankx/send", ""},
+		{"staking", ""},
+		{"", ""},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.route, routedTo(rtr, tc.path), tc.path)
+	}
+}
+
+func TestRouterAddRoute(t *testing.T) {
+	rtr := NewRouter()
+	rtr.AddRoute("bank/send", namedHandler("bank/send"))
+
+	// Duplicate routes.
+	assert.Panics(t, func() { rtr.AddRoute("bank/send", namedHandler("again")) })
+
+	// Malformed routes.
+	for _, r := range []string{"", "/", "bank/", "/bank", "bank//send", "bank-send", "bank send"} {
+		assert.Panics(t, func() { rtr.AddRoute(r, namedHandler(r)) }, r)
+	}
+}
+
+func TestRouterAnteHandler(t *testing.T) {
+	rtr := NewRouter()
+	rtr.AddRoute("bank", namedHandler("bank"))
+	rtr.AddRouteWithAnteHandler("bank/issue", func(ctx sdk.Context, tx sdk.Tx) (sdk.Context, sdk.Result, bool) {
+		return ctx, sdk.Result{}, false
+	}, namedHandler("bank/issue"))
+
+	r, ah := rtr.AnteHandler("bank/send")
+	assert.Equal(t, "bank", r)
+	assert.Nil(t, ah)
+
+	r, ah = rtr.AnteHandler("bank/issue/more")
+	assert.Equal(t, "bank/issue", r)
+	assert.NotNil(t, ah)
+	assert.Equal(t, "bank/issue", routedTo(rtr, "bank/issue/more"))
+
+	r, ah = rtr.AnteHandler("staking")
+	assert.Equal(t, "", r)
+	assert.Nil(t, ah)
+}
@@ -1,8 +1,10 @@
 package baseapp
 
 import (
+	"math"
+	"time"
+
 	abci "github.com/tendermint/abci/types"
-	"github.com/tendermint/go-crypto"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
@@ -24,16 +26,23 @@ func NewTestApp(bapp *BaseApp) *TestApp {
 	return app
 }
 
+// RunBeginBlock begins the next block at the current time.
 func (tapp *TestApp) RunBeginBlock() {
+	tapp.RunBeginBlockAt(time.Now())
+}
+
+// RunBeginBlockAt begins the next block at blockTime, which is
+// truncated to seconds by abci.Header.
+func (tapp *TestApp) RunBeginBlockAt(blockTime time.Time) {
 	if tapp.header != nil {
-		panic("TestApp.header not nil, BeginBlock already run, or EndBlock not yet run.")
+		panic("TestApp.header not nil, BeginBlock already run, or Commit not yet run.")
 	}
 	cms := tapp.CommitMultiStore()
 	lastCommit := cms.LastCommitID()
 	header := abci.Header{
 		ChainID:        "chain_" + tapp.BaseApp.name,
 		Height:         lastCommit.Version + 1,
-		Time:           -1, // TODO
+		Time:           blockTime.Unix(),
 		NumTxs:         -1, // TODO
 		LastCommitHash: lastCommit.Hash,
 		DataHash:       nil, // TODO
@@ -58,12 +67,12 @@ func (tapp *TestApp) ensureBeginBlock() {
 
 func (tapp *TestApp) RunCheckTx(tx sdk.Tx) sdk.Result {
 	tapp.ensureBeginBlock()
-	return tapp.BaseApp.runTx(true, nil, tx)
+	return tapp.BaseApp.runTx(runTxModeCheck, nil, tx)
 }
 
 func (tapp *TestApp) RunDeliverTx(tx sdk.Tx) sdk.Result {
 	tapp.ensureBeginBlock()
-	return tapp.BaseApp.runTx(false, nil, tx)
+	return tapp.BaseApp.runTx(runTxModeDeliver, nil, tx)
 }
 
 // NOTE: Skips authentication by wrapping msg in testTx{}.
@@ -97,10 +106,11 @@ type testTx struct {
 	sdk.Msg
 }
 
-func (tx testTx) GetMsg() sdk.Msg                   { return tx.Msg }
-func (tx testTx) GetSigners() []crypto.Address      { return nil }
-func (tx testTx) GetFeePayer() crypto.Address       { return nil }
+func (tx testTx) GetMsgs() []sdk.Msg                { return []sdk.Msg{tx.Msg} }
+func (tx testTx) GetSigners() []sdk.Address         { return nil }
+func (tx testTx) GetFeePayer() sdk.Address          { return nil }
 func (tx testTx) GetSignatures() []sdk.StdSignature { return nil }
+func (tx testTx) GetFee() sdk.StdFee                { return sdk.NewStdFee(math.MaxInt64) }
 
 func IsTestAppTx(tx sdk.Tx) bool {
 	_, ok := tx.(testTx)
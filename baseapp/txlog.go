@@ -0,0 +1,116 @@
+package baseapp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/tendermint/tmlibs/log"
+)
+
+// Maximum number of bytes of log output captured per tx.
+const maxTxLogSize = 4096
+
+const txLogTruncated = "... (truncated)\n"
+
+// txLog captures the log output of a single tx, up to a limit.
+// Writes beyond the limit are dropped.
+type txLog struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func newTxLog(limit int) *txLog {
+	return &txLog{limit: limit}
+}
+
+// Implements io.Writer.
+func (tl *txLog) Write(p []byte) (int, error) {
+	if room := tl.limit - tl.buf.Len(); len(p) > room {
+		if room > 0 {
+			tl.buf.Write(p[:room])
+		}
+		tl.truncated = true
+		return len(p), nil
+	}
+	return tl.buf.Write(p)
+}
+
+// Logger returns a logger that writes into the txLog.
+func (tl *txLog) Logger() log.Logger {
+	return txLogger{w: log.NewSyncWriter(tl)}
+}
+
+// String returns the captured output.
+func (tl *txLog) String() string {
+	if tl.truncated {
+		return tl.buf.String() + txLogTruncated
+	}
+	return tl.buf.String()
+}
+
+// Appends the captured output to a Result's log.
+func appendTxLog(resultLog string, tl *txLog) string {
+	captured := tl.String()
+	if captured == "" {
+		return resultLog
+	}
+	if resultLog == "" {
+		return captured
+	}
+	return resultLog + "\n" + captured
+}
+
+//----------------------------------------
+// txLogger
+
+// txLogger writes a line per entry, like the TMLogger but without a
+// timestamp, so that a tx logs the same on every node and replay.
+type txLogger struct {
+	w       io.Writer
+	keyvals []interface{}
+}
+
+var _ log.Logger = txLogger{}
+
+// Implements log.Logger.
+func (l txLogger) Debug(msg string, keyvals ...interface{}) {
+	l.log('D', msg, keyvals)
+}
+
+// Implements log.Logger.
+func (l txLogger) Info(msg string, keyvals ...interface{}) {
+	l.log('I', msg, keyvals)
+}
+
+// Implements log.Logger.
+func (l txLogger) Error(msg string, keyvals ...interface{}) {
+	l.log('E', msg, keyvals)
+}
+
+// Implements log.Logger.
+func (l txLogger) With(keyvals ...interface{}) log.Logger {
+	return txLogger{
+		w:       l.w,
+		keyvals: append(append([]interface{}{}, l.keyvals...), keyvals...),
+	}
+}
+
+// log writes "<level> <msg> key=value ...", e.g. "I Sent coins amount=5".
+func (l txLogger) log(level byte, msg string, keyvals []interface{}) {
+	var buf bytes.Buffer
+	buf.WriteByte(level)
+	buf.WriteString(" ")
+	buf.WriteString(msg)
+	kvs := append(append([]interface{}{}, l.keyvals...), keyvals...)
+	for i := 0; i < len(kvs); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(kvs) {
+			value = kvs[i+1]
+		}
+		fmt.Fprintf(&buf, " %v=%v", kvs[i], value)
+	}
+	buf.WriteByte('\n')
+	l.w.Write(buf.Bytes()) // nolint: errcheck
+}
@@ -6,9 +6,12 @@ import (
 
 	bam "github.com/cosmos/cosmos-sdk/baseapp"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/tendermint/abci/server"
 	"github.com/tendermint/go-wire"
 	cmn "github.com/tendermint/tmlibs/common"
+	dbm "github.com/tendermint/tmlibs/db"
 )
 
 const appName = "BasecoinApp"
@@ -25,25 +28,35 @@ type BasecoinApp struct {
 
 	// Object mappers:
 	accountMapper sdk.AccountMapper
+
+	// Collects the fees deducted from txs.
+	feePool auth.FeePool
+
+	// Describes the denoms and their display units.
+	denomMapper bank.DenomMapper
 }
 
+// NewBasecoinApp returns a BasecoinApp which persists its state to db,
+// loaded at its latest version.  It errors if the state can't be
+// loaded.
 // TODO: This should take in more configuration options.
-func NewBasecoinApp() *BasecoinApp {
+func NewBasecoinApp(db dbm.DB) (*BasecoinApp, error) {
 
 	// Create and configure app.
 	var app = &BasecoinApp{}
-	app.initCapKeys()  // ./init_capkeys.go
-	app.initBaseApp()  // ./init_baseapp.go
-	app.initStores()   // ./init_stores.go
-	app.initHandlers() // ./init_handlers.go
+	app.initCapKeys()   // ./init_capkeys.go
+	app.initBaseApp(db) // ./init_baseapp.go
+	app.initStores()    // ./init_stores.go
+	app.initHandlers()  // ./init_handlers.go
+	app.initGenesis()   // ./init_genesis.go
 
-	// TODO: Load genesis
 	// TODO: InitChain with validators
-	// TODO: Set the genesis accounts
 
-	app.loadStores()
+	if err := app.loadStores(); err != nil {
+		return nil, err
+	}
 
-	return app
+	return app, nil
 }
 
 func (app *BasecoinApp) RunForever() {
@@ -65,9 +78,6 @@ func (app *BasecoinApp) RunForever() {
 }
 
 // Load the stores.
-func (app *BasecoinApp) loadStores() {
-	if err := app.LoadLatestVersion(app.capKeyMainStore); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
+func (app *BasecoinApp) loadStores() error {
+	return app.LoadLatestVersion(app.capKeyMainStore)
 }
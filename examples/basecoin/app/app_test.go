@@ -1,14 +1,86 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/cosmos/cosmos-sdk/examples/basecoin/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/stretchr/testify/assert"
+	abci "github.com/tendermint/abci/types"
 	crypto "github.com/tendermint/go-crypto"
+	dbm "github.com/tendermint/tmlibs/db"
 )
 
+// testChainID is the chain id set by TestApp.RunBeginBlock.
+const testChainID = "chain_" + appName
+
+// setGenesis initializes the chain with the genesis accounts accs, and
+// commits the first block.
+func setGenesis(tba *testBasecoinApp, accs ...*types.GenesisAccount) {
+	setGenesisState(tba, types.GenesisState{Accounts: accs})
+}
+
+// setGenesisState initializes the chain with genesisState, and commits
+// the first block.
+func setGenesisState(tba *testBasecoinApp, genesisState types.GenesisState) {
+	stateBytes, err := json.Marshal(genesisState)
+	if err != nil {
+		panic(err)
+	}
+	tba.BasecoinApp.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+	tba.RunBeginBlock()
+	tba.BasecoinApp.EndBlock(abci.RequestEndBlock{})
+	tba.BasecoinApp.Commit()
+}
+
+// signTx returns a tx of msgs paying fee, signed by privs for the
+// accounts accNums at the sequences seqs.
+func signTx(msgs []sdk.Msg, fee sdk.StdFee, privs []crypto.PrivKey, accNums, seqs []int64) sdk.StdTx {
+	signBytes := sdk.StdSignBytes(testChainID, accNums, seqs, fee, msgs)
+	sigs := make([]sdk.StdSignature, len(privs))
+	for i, priv := range privs {
+		sigs[i] = sdk.StdSignature{
+			PubKey:        priv.PubKey(),
+			Signature:     priv.Sign(signBytes),
+			AccountNumber: accNums[i],
+			Sequence:      seqs[i],
+		}
+	}
+	return sdk.NewStdTx(msgs, fee, sigs)
+}
+
+// marshalTx returns the bytes of tx, as sent to CheckTx and DeliverTx.
+func marshalTx(t *testing.T, tx sdk.StdTx) []byte {
+	txBytes, err := makeTxCodec().MarshalBinary(tx)
+	assert.Nil(t, err)
+	return txBytes
+}
+
+// newSendMsg returns a SendMsg of coins from one address to another.
+func newSendMsg(from, to sdk.Address, coins sdk.Coins) bank.SendMsg {
+	return bank.NewSendMsg(
+		[]bank.Input{bank.NewInput(from, coins)},
+		[]bank.Output{bank.NewOutput(to, coins)},
+	)
+}
+
+// getAccount returns the account at addr in the deliver state.
+func getAccount(tba *testBasecoinApp, addr sdk.Address) sdk.Account {
+	ctx := sdk.NewContext(tba.MultiStoreDeliver(), abci.Header{}, false, nil)
+	return tba.accountMapper.GetAccount(ctx, addr)
+}
+
+// getCoins returns the coins of the account at addr in the deliver
+// state.
+func getCoins(tba *testBasecoinApp, addr sdk.Address) sdk.Coins {
+	return getAccount(tba, addr).GetCoins()
+}
+
 func TestSendMsg(t *testing.T) {
 	tba := newTestBasecoinApp()
 	tba.RunBeginBlock()
@@ -17,24 +89,570 @@ func TestSendMsg(t *testing.T) {
 	var msg = bank.SendMsg{
 		Inputs: []bank.Input{
 			{
-				Address:  crypto.Address([]byte("input")),
-				Coins:    sdk.Coins{{"atom", 10}},
+				Address:  sdk.Address([]byte("input")),
+				Coins:    sdk.Coins{sdk.NewCoin("atom", 10)},
 				Sequence: 1,
 			},
 		},
 		Outputs: []bank.Output{
 			{
-				Address: crypto.Address([]byte("output")),
-				Coins:   sdk.Coins{{"atom", 10}},
+				Address: sdk.Address([]byte("output")),
+				Coins:   sdk.Coins{sdk.NewCoin("atom", 10)},
 			},
 		},
 	}
 
 	// Run a Check on SendMsg.
 	res := tba.RunCheckMsg(msg)
-	assert.Equal(t, sdk.CodeOK, res.Code, res.Log)
+	assert.Equal(t, sdk.ABCICodeOK, res.ABCICode(), res.Log)
 
 	// Run a Deliver on SendMsg.
 	res = tba.RunDeliverMsg(msg)
-	assert.Equal(t, sdk.CodeUnrecognizedAddress, res.Code, res.Log)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnrecognizedAddress), res.ABCICode(), res.Log)
+}
+
+func TestGenesis(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	addr := sdk.Address([]byte("input"))
+	coins := sdk.Coins{sdk.NewCoin("atom", 10), sdk.NewCoin("photon", 5)}
+	genesisState := types.GenesisState{
+		Accounts: []*types.GenesisAccount{
+			{
+				Name:    "alice",
+				Address: addr,
+				Coins:   coins,
+			},
+		},
+		Denoms: []sdk.DenomMetadata{
+			{Base: "uatom", Display: "atom", Exponent: 6, Description: "The staking token"},
+		},
+		ConsensusParams: &sdk.ConsensusParams{MaxBlockGas: 1000000},
+	}
+
+	// Initialize the chain and commit the first block.
+	setGenesisState(tba, genesisState)
+
+	// The genesis account is in the committed state.
+	ctx := sdk.NewContext(tba.CommitMultiStore(), abci.Header{}, false, nil)
+	acc := tba.accountMapper.GetAccount(ctx, addr)
+	if assert.NotNil(t, acc) {
+		assert.Equal(t, coins, acc.GetCoins())
+		assert.Equal(t, "alice", acc.(*types.AppAccount).GetName())
+	}
+
+	// The genesis denoms can be looked up by base or display denom.
+	meta, ok := tba.denomMapper.GetDenomMetadata(ctx, "atom")
+	if assert.True(t, ok) {
+		assert.Equal(t, genesisState.Denoms[0], meta)
+	}
+	parsed, err := sdk.ParseDisplayCoins("1.5atom", tba.denomMapper.DenomLookup(ctx))
+	assert.Nil(t, err)
+	assert.Equal(t, "1500000uatom", parsed.String())
+	_, ok = tba.denomMapper.GetDenomMetadata(ctx, "photon")
+	assert.False(t, ok)
+
+	// The genesis account can now send coins.
+	tba.RunBeginBlock()
+	assert.Equal(t, *genesisState.ConsensusParams, tba.BasecoinApp.ConsensusParams())
+	res := tba.RunDeliverMsg(newSendMsg(addr, sdk.Address([]byte("output")), sdk.Coins{sdk.NewCoin("atom", 7)}))
+	assert.Equal(t, sdk.ABCICodeOK, res.ABCICode(), res.Log)
+}
+
+func TestMultiMsgs(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	priv1 := crypto.GenPrivKeyEd25519()
+	priv2 := crypto.GenPrivKeyEd25519()
+	addr1 := sdk.NewAddress(priv1.PubKey())
+	addr2 := sdk.NewAddress(priv2.PubKey())
+	addr3 := sdk.Address([]byte("output"))
+	setGenesis(tba,
+		&types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 10)}},
+		&types.GenesisAccount{Name: "bob", Address: addr2, Coins: sdk.Coins{sdk.NewCoin("atom", 10)}},
+	)
+
+	send := func(from sdk.Address, amount int64) sdk.Msg {
+		return newSendMsg(from, addr3, sdk.Coins{sdk.NewCoin("atom", amount)})
+	}
+	privs := []crypto.PrivKey{priv1, priv2}
+	fee := sdk.NewStdFee(1000000)
+	// Genesis accounts are numbered in order.
+	accNums := []int64{0, 1}
+
+	// Signers are deduplicated, so alice signs once.
+	tba.RunBeginBlock()
+	tx := signTx([]sdk.Msg{send(addr1, 3), send(addr2, 5), send(addr1, 2)}, fee, privs, accNums, []int64{0, 0})
+	assert.Equal(t, []sdk.Address{addr1, addr2}, tx.GetSigners())
+	res := tba.RunDeliverTx(tx)
+	assert.Equal(t, sdk.ABCICodeOK, res.ABCICode(), res.Log)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5)}, getCoins(tba, addr1))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5)}, getCoins(tba, addr2))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, getCoins(tba, addr3))
+
+	// If any Msg fails, none of them are applied.
+	tx = signTx([]sdk.Msg{send(addr1, 5), send(addr2, 50)}, fee, privs, accNums, []int64{1, 1})
+	res = tba.RunDeliverTx(tx)
+	assert.Equal(t, sdk.ToABCICode(bank.DefaultCodespace, bank.CodeInsufficientCoins), res.ABCICode(), res.Log)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5)}, getCoins(tba, addr1))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5)}, getCoins(tba, addr2))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, getCoins(tba, addr3))
+}
+
+func TestSimulateSkipSigs(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	addr1 := sdk.Address([]byte("input"))
+	addr2 := sdk.Address([]byte("output"))
+	setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 10)}})
+
+	// An unsigned tx.
+	msg := newSendMsg(addr1, addr2, sdk.Coins{sdk.NewCoin("atom", 7)})
+	txBytes := marshalTx(t, sdk.NewStdTx([]sdk.Msg{msg}, sdk.NewStdFee(1000000), nil))
+
+	// It can only be simulated if signatures are skipped.
+	res := tba.BasecoinApp.Query(abci.RequestQuery{Path: "/app/simulate", Data: txBytes})
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), sdk.ABCICodeType(res.Code), res.Log)
+	res = tba.BasecoinApp.Query(abci.RequestQuery{Path: "/app/simulate/skipsigs", Data: txBytes})
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+
+	var result sdk.Result
+	err := json.Unmarshal(res.Value, &result)
+	assert.Nil(t, err)
+	assert.True(t, result.GasUsed > 0)
+
+	// Nothing was committed.
+	ctx := sdk.NewContext(tba.CommitMultiStore(), abci.Header{}, false, nil)
+	acc := tba.accountMapper.GetAccount(ctx, addr1)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, acc.GetCoins())
+	assert.Equal(t, int64(0), acc.GetSequence())
+	assert.Nil(t, tba.accountMapper.GetAccount(ctx, addr2))
+}
+
+func TestFees(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	priv1 := crypto.GenPrivKeyEd25519()
+	addr1 := sdk.NewAddress(priv1.PubKey())
+	addr2 := sdk.Address([]byte("output"))
+	setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 100)}})
+
+	sendTx := func(fee sdk.StdFee, amount int64, seq int64) []byte {
+		msgs := []sdk.Msg{newSendMsg(addr1, addr2, sdk.Coins{sdk.NewCoin("atom", amount)})}
+		return marshalTx(t, signTx(msgs, fee, []crypto.PrivKey{priv1}, []int64{0}, []int64{seq}))
+	}
+	getFees := func() sdk.Coins {
+		ctx := sdk.NewContext(tba.MultiStoreDeliver(), abci.Header{}, false, nil)
+		return tba.feePool.GetCollectedFees(ctx)
+	}
+	tba.RunBeginBlock()
+
+	// CheckTx reports the fee.
+	fee := sdk.NewStdFee(1000000, sdk.NewCoin("atom", 5))
+	checkRes := tba.BasecoinApp.CheckTx(sendTx(fee, 10, 0))
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(checkRes.Code), checkRes.Log)
+	assert.Equal(t, []byte("atom"), checkRes.Fee.Key)
+	assert.Equal(t, int64(5), checkRes.Fee.Value)
+
+	// The fee is deducted from the payer and collected.
+	res := tba.BasecoinApp.DeliverTx(sendTx(fee, 10, 0))
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 85)}, getCoins(tba, addr1))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, getCoins(tba, addr2))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 5)}, getFees())
+
+	// The fee is kept even if the Msgs fail.
+	res = tba.BasecoinApp.DeliverTx(sendTx(fee, 1000, 1))
+	// The ABCI code includes the codespace of x/bank.
+	assert.Equal(t, uint32(sdk.ToABCICode(bank.DefaultCodespace, bank.CodeInsufficientCoins)), res.Code, res.Log)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 80)}, getCoins(tba, addr1))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, getFees())
+
+	// Nothing is deducted if the ante handler aborts.
+	res = tba.BasecoinApp.DeliverTx(sendTx(fee, 10, 0)) // bad sequence
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInvalidSequence), sdk.ABCICodeType(res.Code), res.Log)
+	res = tba.BasecoinApp.DeliverTx(sendTx(sdk.NewStdFee(1000000, sdk.NewCoin("atom", 81)), 10, 2))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInsufficientFunds), sdk.ABCICodeType(res.Code), res.Log)
+	res = tba.BasecoinApp.DeliverTx(sendTx(sdk.NewStdFee(1000000, sdk.NewCoin("atom", -5)), 10, 2))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeTxParse), sdk.ABCICodeType(res.Code), res.Log)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 80)}, getCoins(tba, addr1))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, getFees())
+}
+
+func TestSignDoc(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	priv1 := crypto.GenPrivKeyEd25519()
+	addr1 := sdk.NewAddress(priv1.PubKey())
+	addr2 := sdk.Address([]byte("output"))
+	setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 100)}})
+
+	msgs := []sdk.Msg{newSendMsg(addr1, addr2, sdk.Coins{sdk.NewCoin("atom", 10)})}
+	fee := sdk.NewStdFee(1000000, sdk.NewCoin("atom", 1))
+	// signDoc signs another document than the one of the tx.
+	signDoc := func(chainID string, accNums []int64, seqs []int64, signedFee sdk.StdFee) sdk.Tx {
+		sig := priv1.Sign(sdk.StdSignBytes(chainID, accNums, seqs, signedFee, msgs))
+		sigs := []sdk.StdSignature{{PubKey: priv1.PubKey(), Signature: sig, AccountNumber: 0, Sequence: 0}}
+		return sdk.NewStdTx(msgs, fee, sigs)
+	}
+	tba.RunBeginBlock()
+
+	// Signatures for another chain, account number, sequence or fee
+	// are rejected.
+	res := tba.RunDeliverTx(signDoc("other-chain", []int64{0}, []int64{0}, fee))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), res.ABCICode(), res.Log)
+	res = tba.RunDeliverTx(signDoc(testChainID, []int64{1}, []int64{0}, fee))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), res.ABCICode(), res.Log)
+	res = tba.RunDeliverTx(signDoc(testChainID, []int64{0}, []int64{1}, fee))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), res.ABCICode(), res.Log)
+	res = tba.RunDeliverTx(signDoc(testChainID, []int64{0}, []int64{0}, sdk.NewStdFee(1000000)))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), res.ABCICode(), res.Log)
+
+	res = tba.RunDeliverTx(signTx(msgs, fee, []crypto.PrivKey{priv1}, []int64{0}, []int64{0}))
+	assert.Equal(t, sdk.ABCICodeOK, res.ABCICode(), res.Log)
+}
+
+func TestMultisigSend(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	// A treasury held by three officers, any two of which can spend.
+	privs := []crypto.PrivKey{
+		crypto.GenPrivKeyEd25519(), crypto.GenPrivKeyEd25519(), crypto.GenPrivKeyEd25519(),
+	}
+	pubKeys := []crypto.PubKey{privs[0].PubKey(), privs[1].PubKey(), privs[2].PubKey()}
+	treasuryKey := auth.NewPubKeyMultisigThreshold(2, pubKeys)
+	treasury := sdk.NewAddress(treasuryKey)
+	addr2 := sdk.Address([]byte("output"))
+	setGenesis(tba, &types.GenesisAccount{Name: "treasury", Address: treasury, Coins: sdk.Coins{sdk.NewCoin("atom", 100)}})
+
+	msgs := []sdk.Msg{newSendMsg(treasury, addr2, sdk.Coins{sdk.NewCoin("atom", 10)})}
+	fee := sdk.NewStdFee(1000000)
+	multisign := func(seq int64, signers ...int) []byte {
+		signBytes := sdk.StdSignBytes(testChainID, []int64{0}, []int64{seq}, fee, msgs)
+		multisig := auth.NewMultisignature(len(pubKeys))
+		for _, i := range signers {
+			multisig.AddSignature(privs[i].Sign(signBytes), i)
+		}
+		sigs := []sdk.StdSignature{{PubKey: treasuryKey, Signature: multisig, Sequence: seq}}
+		return marshalTx(t, sdk.NewStdTx(msgs, fee, sigs))
+	}
+	tba.RunBeginBlock()
+
+	// An officer can't claim the treasury with their own key.
+	txBytes := marshalTx(t, signTx(msgs, fee, privs[:1], []int64{0}, []int64{0}))
+	res := tba.BasecoinApp.DeliverTx(txBytes)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), sdk.ABCICodeType(res.Code), res.Log)
+
+	// One officer can't spend.
+	res = tba.BasecoinApp.DeliverTx(multisign(0, 1))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), sdk.ABCICodeType(res.Code), res.Log)
+
+	// Any two can.
+	res = tba.BasecoinApp.DeliverTx(multisign(0, 0, 2))
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+	res = tba.BasecoinApp.DeliverTx(multisign(1, 2, 1))
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 80)}, getCoins(tba, treasury))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 20)}, getCoins(tba, addr2))
+
+	// The stored treasury key is used, not the key of the signature.
+	txBytes = marshalTx(t, signTx(msgs, fee, privs[:1], []int64{0}, []int64{2}))
+	res = tba.BasecoinApp.DeliverTx(txBytes)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), sdk.ABCICodeType(res.Code), res.Log)
+}
+
+func TestAccountNumberReplay(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	priv1 := crypto.GenPrivKeyEd25519()
+	addr1 := sdk.NewAddress(priv1.PubKey())
+	addr2 := sdk.Address([]byte("output"))
+	setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 10)}})
+
+	coins := sdk.Coins{sdk.NewCoin("atom", 10)}
+	msgs := []sdk.Msg{newSendMsg(addr1, addr2, coins)}
+	tx := signTx(msgs, sdk.NewStdFee(1000000), []crypto.PrivKey{priv1}, []int64{0}, []int64{0})
+	tba.RunBeginBlock()
+
+	// Alice sends everything, and the new account gets the next number.
+	res := tba.RunDeliverTx(tx)
+	assert.Equal(t, sdk.ABCICodeOK, res.ABCICode(), res.Log)
+	assert.Equal(t, int64(1), getAccount(tba, addr2).GetAccountNumber())
+
+	// Alice's account is deleted, then recreated by a deposit.
+	ctx := sdk.NewContext(tba.MultiStoreDeliver(), abci.Header{}, false, nil)
+	ctx.KVStore(tba.capKeyMainStore).Delete(auth.AddressStoreKey(addr1))
+	res = tba.RunDeliverMsg(newSendMsg(addr2, addr1, coins))
+	assert.Equal(t, sdk.ABCICodeOK, res.ABCICode(), res.Log)
+	acc := getAccount(tba, addr1)
+	assert.Equal(t, int64(2), acc.GetAccountNumber())
+	assert.Equal(t, int64(0), acc.GetSequence())
+
+	// The old tx can't be replayed.
+	res = tba.RunDeliverTx(tx)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeInvalidSequence), res.ABCICode(), res.Log)
+	assert.Equal(t, coins, getCoins(tba, addr1))
+}
+
+func TestDumpState(t *testing.T) {
+	db := dbm.NewMemDB()
+	tba := newTestBasecoinAppWithDB(db)
+
+	priv1 := crypto.GenPrivKeyEd25519()
+	addr1 := sdk.NewAddress(priv1.PubKey())
+	addr2 := sdk.Address([]byte("output"))
+	setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 10)}})
+
+	// Alice sends coins to a new account in block 2.
+	coins := sdk.Coins{sdk.NewCoin("atom", 3)}
+	msgs := []sdk.Msg{newSendMsg(addr1, addr2, coins)}
+	tba.RunBeginBlock()
+	res := tba.RunDeliverTx(signTx(msgs, sdk.NewStdFee(1000000), []crypto.PrivKey{priv1}, []int64{0}, []int64{0}))
+	assert.Equal(t, sdk.ABCICodeOK, res.ABCICode(), res.Log)
+	tba.BasecoinApp.EndBlock(abci.RequestEndBlock{})
+	tba.BasecoinApp.Commit()
+
+	// The latest state has both accounts, in the order of their addresses.
+	dump, err := tba.DumpState()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), dump.Height)
+	pubKey, err := sdk.Bech32ifyAccPub(priv1.PubKey())
+	assert.Nil(t, err)
+	alice := AccountDump{Name: "alice", Address: addr1, PubKey: pubKey,
+		AccountNumber: 0, Sequence: 1, Coins: sdk.Coins{sdk.NewCoin("atom", 7)}}
+	bob := AccountDump{Address: addr2, AccountNumber: 1, Coins: coins}
+	if bytes.Compare(addr1, addr2) < 0 {
+		assert.Equal(t, []AccountDump{alice, bob}, dump.Accounts)
+	} else {
+		assert.Equal(t, []AccountDump{bob, alice}, dump.Accounts)
+	}
+
+	// The state at height 1 only has the genesis account.
+	bapp, err := NewBasecoinApp(db)
+	assert.Nil(t, err)
+	err = bapp.LoadHeight(1)
+	assert.Nil(t, err)
+	dump, err = bapp.DumpState()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), dump.Height)
+	assert.Equal(t, []AccountDump{
+		{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 10)}},
+	}, dump.Accounts)
+
+	// Missing heights are an error.
+	err = bapp.LoadHeight(3)
+	assert.NotNil(t, err)
+}
+
+func TestDumpMultisigPubKey(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	pubKeys := []crypto.PubKey{
+		crypto.GenPrivKeyEd25519().PubKey(), crypto.GenPrivKeyEd25519().PubKey(), crypto.GenPrivKeyEd25519().PubKey(),
+	}
+	treasuryKey := auth.NewPubKeyMultisigThreshold(2, pubKeys)
+	treasury := sdk.NewAddress(treasuryKey)
+
+	// A multisig pubkey is too long for bech32.
+	_, err := sdk.Bech32ifyAccPub(treasuryKey)
+	assert.NotNil(t, err)
+
+	setGenesis(tba)
+	tba.RunBeginBlock()
+	ctx := sdk.NewContext(tba.MultiStoreDeliver(), abci.Header{}, false, nil)
+	acc := tba.accountMapper.NewAccountWithAddress(ctx, treasury)
+	err = acc.SetPubKey(treasuryKey)
+	assert.Nil(t, err)
+	tba.accountMapper.SetAccount(ctx, acc)
+	tba.BasecoinApp.EndBlock(abci.RequestEndBlock{})
+	tba.BasecoinApp.Commit()
+
+	// It's dumped as its threshold and member keys, which decode back.
+	dump, err := tba.DumpState()
+	assert.Nil(t, err)
+	bz, err := json.Marshal(dump)
+	assert.Nil(t, err)
+	var dump2 StateDump
+	err = json.Unmarshal(bz, &dump2)
+	assert.Nil(t, err)
+	if assert.Len(t, dump2.Accounts, 1) && assert.NotNil(t, dump2.Accounts[0].MultisigPubKey) {
+		assert.Empty(t, dump2.Accounts[0].PubKey)
+		pubKey, err := dump2.Accounts[0].MultisigPubKey.PubKey()
+		assert.Nil(t, err)
+		assert.True(t, treasuryKey.Equals(pubKey))
+	}
+
+	// Invalid thresholds are rejected.
+	_, err = MultisigPubKeyDump{Threshold: 0, PubKeys: dump2.Accounts[0].MultisigPubKey.PubKeys}.PubKey()
+	assert.NotNil(t, err)
+}
+
+func TestVestingSend(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	// Alice's coins vest linearly over 1000s, Bob's at once at the end.
+	priv1, priv2 := crypto.GenPrivKeyEd25519(), crypto.GenPrivKeyEd25519()
+	addr1, addr2 := sdk.NewAddress(priv1.PubKey()), sdk.NewAddress(priv2.PubKey())
+	addr3 := sdk.Address([]byte("output"))
+	setGenesis(tba,
+		&types.GenesisAccount{Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 100)},
+			VestingStartTime: 1000, VestingEndTime: 2000},
+		&types.GenesisAccount{Address: addr2, Coins: sdk.Coins{sdk.NewCoin("atom", 100)},
+			VestingEndTime: 2000},
+	)
+
+	fee := sdk.NewStdFee(1000000)
+	// The ante handler bumps the sequence even if the send fails.
+	seqs := map[int64]int64{}
+	send := func(priv crypto.PrivKey, accNum, amount int64) abci.ResponseDeliverTx {
+		seq := seqs[accNum]
+		seqs[accNum]++
+		msgs := []sdk.Msg{newSendMsg(sdk.NewAddress(priv.PubKey()), addr3, sdk.Coins{sdk.NewCoin("atom", amount)})}
+		tx := signTx(msgs, fee, []crypto.PrivKey{priv}, []int64{accNum}, []int64{seq})
+		return tba.BasecoinApp.DeliverTx(marshalTx(t, tx))
+	}
+	nextBlock := func(blockTime int64) {
+		tba.BasecoinApp.EndBlock(abci.RequestEndBlock{})
+		tba.BasecoinApp.Commit()
+		tba.RunBeginBlockAt(time.Unix(blockTime, 0))
+	}
+	insufficient := uint32(sdk.ToABCICode(bank.DefaultCodespace, bank.CodeInsufficientCoins))
+
+	// Nothing has vested at the start.
+	tba.RunBeginBlockAt(time.Unix(1000, 0))
+	res := send(priv1, 0, 1)
+	assert.Equal(t, insufficient, res.Code, res.Log)
+	res = send(priv2, 1, 1)
+	assert.Equal(t, insufficient, res.Code, res.Log)
+
+	// Halfway, half of Alice's coins vested, and none of Bob's.
+	nextBlock(1500)
+	res = send(priv1, 0, 51)
+	assert.Equal(t, insufficient, res.Code, res.Log)
+	res = send(priv1, 0, 50)
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+	res = send(priv2, 1, 1)
+	assert.Equal(t, insufficient, res.Code, res.Log)
+
+	// At the end, everything vested.
+	nextBlock(2000)
+	res = send(priv1, 0, 50)
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+	res = send(priv2, 1, 100)
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 200)}, getCoins(tba, addr3))
+}
+
+func TestGenesisVestingName(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	// A vesting account would lose its name, so it can't have one.
+	assert.Panics(t, func() {
+		setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: sdk.Address([]byte("input")),
+			Coins: sdk.Coins{sdk.NewCoin("atom", 100)}, VestingEndTime: 2000})
+	})
+}
+
+func TestModuleAccount(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	priv1 := crypto.GenPrivKeyEd25519()
+	addr1 := sdk.NewAddress(priv1.PubKey())
+	addr2 := sdk.Address([]byte("output"))
+	setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 100)}})
+
+	// An escrow, and a module that mints and burns.
+	tba.RunBeginBlock()
+	ctx := sdk.NewContext(tba.MultiStoreDeliver(), abci.Header{}, false, nil)
+	for _, macc := range []*auth.ModuleAccount{
+		auth.NewModuleAccount("escrow"),
+		auth.NewModuleAccount("mint", auth.Minter, auth.Burner),
+	} {
+		macc.SetAccountNumber(tba.accountMapper.GetNextAccountNumber(ctx))
+		tba.accountMapper.SetAccount(ctx, macc)
+	}
+	escrow := auth.NewModuleAddress("escrow")
+
+	// Only permitted modules mint and burn.
+	cm := bank.NewCoinMapper(tba.accountMapper, nil)
+	coins := sdk.Coins{sdk.NewCoin("atom", 50)}
+	_, sdkErr := cm.MintCoins(ctx, "escrow", coins)
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), sdkErr.ABCICode())
+	_, sdkErr = cm.MintCoins(ctx, "mint", coins)
+	assert.Nil(t, sdkErr)
+	_, sdkErr = cm.BurnCoins(ctx, "mint", sdk.Coins{sdk.NewCoin("atom", 20)})
+	assert.Nil(t, sdkErr)
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 30)}, getCoins(tba, auth.NewModuleAddress("mint")))
+
+	// Coins move in and out of module accounts.
+	sdkErr = cm.SendCoinsFromAccountToModule(ctx, addr1, "escrow", sdk.Coins{sdk.NewCoin("atom", 40)})
+	assert.Nil(t, sdkErr)
+	sdkErr = cm.SendCoinsFromModuleToAccount(ctx, "escrow", addr2, sdk.Coins{sdk.NewCoin("atom", 10)})
+	assert.Nil(t, sdkErr)
+	sdkErr = cm.SendCoinsFromModuleToAccount(ctx, "missing", addr2, sdk.Coins{sdk.NewCoin("atom", 10)})
+	assert.Equal(t, sdk.ToABCICode(bank.DefaultCodespace, bank.CodeUnknownAddress), sdkErr.ABCICode())
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 60)}, getCoins(tba, addr1))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 30)}, getCoins(tba, escrow))
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, getCoins(tba, addr2))
+
+	// But no tx can spend them.
+	msgs := []sdk.Msg{newSendMsg(escrow, addr1, coins)}
+	tx := signTx(msgs, sdk.NewStdFee(1000000), []crypto.PrivKey{priv1}, []int64{2}, []int64{0})
+	res := tba.BasecoinApp.DeliverTx(marshalTx(t, tx))
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), sdk.ABCICodeType(res.Code), res.Log)
+	assert.Contains(t, res.Log, "module account")
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 30)}, getCoins(tba, escrow))
+}
+
+func TestModuleAccountFirstUse(t *testing.T) {
+	tba := newTestBasecoinApp()
+
+	priv1 := crypto.GenPrivKeyEd25519()
+	addr1 := sdk.NewAddress(priv1.PubKey())
+	setGenesis(tba, &types.GenesisAccount{Name: "alice", Address: addr1, Coins: sdk.Coins{sdk.NewCoin("atom", 100)}})
+
+	// Coins sent to the address of a module without an account make a
+	// plain account.
+	tba.RunBeginBlock()
+	mint := auth.NewModuleAddress("mint")
+	msgs := []sdk.Msg{newSendMsg(addr1, mint, sdk.Coins{sdk.NewCoin("atom", 10)})}
+	tx := signTx(msgs, sdk.NewStdFee(1000000), []crypto.PrivKey{priv1}, []int64{0}, []int64{0})
+	res := tba.BasecoinApp.DeliverTx(marshalTx(t, tx))
+	assert.Equal(t, sdk.ABCICodeOK, sdk.ABCICodeType(res.Code), res.Log)
+
+	ctx := sdk.NewContext(tba.MultiStoreDeliver(), abci.Header{}, false, nil)
+	acc := tba.accountMapper.GetAccount(ctx, mint)
+	_, ok := acc.(*auth.ModuleAccount)
+	assert.False(t, ok)
+
+	// A module that isn't given to the CoinMapper can't use it.
+	_, sdkErr := bank.NewCoinMapper(tba.accountMapper, nil).MintCoins(ctx, "mint", sdk.Coins{sdk.NewCoin("atom", 5)})
+	assert.Equal(t, sdk.ToABCICode(bank.DefaultCodespace, bank.CodeInvalidAddress), sdkErr.ABCICode())
+
+	// The first use by the module makes it the module account.
+	cm := bank.NewCoinMapper(tba.accountMapper, map[string][]string{
+		"mint":   {auth.Minter},
+		"escrow": nil,
+	})
+	_, sdkErr = cm.MintCoins(ctx, "mint", sdk.Coins{sdk.NewCoin("atom", 5)})
+	assert.Nil(t, sdkErr)
+	macc, ok := tba.accountMapper.GetAccount(ctx, mint).(*auth.ModuleAccount)
+	assert.True(t, ok)
+	assert.Equal(t, "mint", macc.GetName())
+	assert.Equal(t, acc.GetAccountNumber(), macc.GetAccountNumber())
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 15)}, macc.GetCoins())
+
+	// A module without an account gets a new one, without permissions
+	// it wasn't given.
+	sdkErr = cm.SendCoinsFromAccountToModule(ctx, addr1, "escrow", sdk.Coins{sdk.NewCoin("atom", 20)})
+	assert.Nil(t, sdkErr)
+	_, sdkErr = cm.MintCoins(ctx, "escrow", sdk.Coins{sdk.NewCoin("atom", 5)})
+	assert.Equal(t, sdk.ToABCICode(sdk.CodespaceRoot, sdk.CodeUnauthorized), sdkErr.ABCICode())
+	escrow, ok := tba.accountMapper.GetAccount(ctx, auth.NewModuleAddress("escrow")).(*auth.ModuleAccount)
+	assert.True(t, ok)
+	assert.NotEqual(t, macc.GetAccountNumber(), escrow.GetAccountNumber())
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 20)}, escrow.GetCoins())
+	_, sdkErr = cm.MintCoins(ctx, "missing", sdk.Coins{sdk.NewCoin("atom", 5)})
+	assert.Equal(t, sdk.ToABCICode(bank.DefaultCodespace, bank.CodeUnknownAddress), sdkErr.ABCICode())
 }
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"fmt"
+
+	crypto "github.com/tendermint/go-crypto"
+
+	"github.com/cosmos/cosmos-sdk/examples/basecoin/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+// StateDump is the JSON representation of the state of the chain at a
+// height, as printed by `basecoind dump`.
+type StateDump struct {
+	Height   int64         `json:"height"`
+	Accounts []AccountDump `json:"accounts"`
+}
+
+// AccountDump is the JSON representation of an account in a StateDump.
+type AccountDump struct {
+	Name          string      `json:"name"`
+	Address       sdk.Address `json:"address"`
+	PubKey        string      `json:"public_key,omitempty"` // bech32
+	AccountNumber int64       `json:"account_number"`
+	Sequence      int64       `json:"sequence"`
+	Coins         sdk.Coins   `json:"coins"`
+
+	// A multisig pubkey is too long for bech32, so it's dumped as its
+	// threshold and bech32 member keys instead of PubKey.
+	MultisigPubKey *MultisigPubKeyDump `json:"multisig_public_key,omitempty"`
+}
+
+// MultisigPubKeyDump is the JSON representation of an
+// auth.PubKeyMultisigThreshold in an AccountDump.
+type MultisigPubKeyDump struct {
+	Threshold uint     `json:"threshold"`
+	PubKeys   []string `json:"pubkeys"` // bech32
+}
+
+// NewMultisigPubKeyDump returns the dump of pk.  It errors if a member
+// key can't be bech32 encoded, e.g. a nested multisig key.
+func NewMultisigPubKeyDump(pk auth.PubKeyMultisigThreshold) (*MultisigPubKeyDump, error) {
+	pubKeys := make([]string, len(pk.PubKeys))
+	for i, pubKey := range pk.PubKeys {
+		str, err := sdk.Bech32ifyAccPub(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		pubKeys[i] = str
+	}
+	return &MultisigPubKeyDump{
+		Threshold: pk.K,
+		PubKeys:   pubKeys,
+	}, nil
+}
+
+// PubKey decodes the dumped multisig pubkey.
+func (md MultisigPubKeyDump) PubKey() (auth.PubKeyMultisigThreshold, error) {
+	pubKeys := make([]crypto.PubKey, len(md.PubKeys))
+	for i, str := range md.PubKeys {
+		pubKey, err := sdk.GetAccPubKeyBech32(str)
+		if err != nil {
+			return auth.PubKeyMultisigThreshold{}, err
+		}
+		pubKeys[i] = pubKey
+	}
+	if md.Threshold == 0 || int(md.Threshold) > len(pubKeys) {
+		return auth.PubKeyMultisigThreshold{}, fmt.Errorf("invalid threshold %d of %d pubkeys", md.Threshold, len(pubKeys))
+	}
+	return auth.NewPubKeyMultisigThreshold(md.Threshold, pubKeys), nil
+}
+
+// LoadHeight loads the state committed at height, instead of the latest.
+func (app *BasecoinApp) LoadHeight(height int64) error {
+	return app.LoadVersion(height, app.capKeyMainStore)
+}
+
+// DumpState returns the accounts of the loaded state, in the order of
+// their addresses.
+func (app *BasecoinApp) DumpState() (StateDump, error) {
+	ctx := app.LastCommitContext()
+	dump := StateDump{
+		Height:   app.LastBlockHeight(),
+		Accounts: []AccountDump{},
+	}
+	var err error
+	app.accountMapper.IterateAccounts(ctx, func(acc sdk.Account) bool {
+		accDump := AccountDump{
+			Address:       acc.GetAddress(),
+			AccountNumber: acc.GetAccountNumber(),
+			Sequence:      acc.GetSequence(),
+			Coins:         acc.GetCoins(),
+		}
+		switch acc := acc.(type) {
+		case *types.AppAccount:
+			accDump.Name = acc.GetName()
+		case *auth.ModuleAccount:
+			accDump.Name = acc.GetName()
+		}
+		switch pubKey := acc.GetPubKey().(type) {
+		case nil:
+		case auth.PubKeyMultisigThreshold:
+			accDump.MultisigPubKey, err = NewMultisigPubKeyDump(pubKey)
+		default:
+			accDump.PubKey, err = sdk.Bech32ifyAccPub(pubKey)
+		}
+		if err != nil {
+			return true
+		}
+		dump.Accounts = append(dump.Accounts, accDump)
+		return false
+	})
+	if err != nil {
+		return StateDump{}, err
+	}
+	return dump, nil
+}
@@ -3,11 +3,12 @@ package app
 import (
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	dbm "github.com/tendermint/tmlibs/db"
 )
 
-// initCapKeys, initBaseApp, initStores, initHandlers.
-func (app *BasecoinApp) initBaseApp() {
-	bapp := baseapp.NewBaseApp(appName)
+// initCapKeys, initBaseApp, initStores, initHandlers, initGenesis.
+func (app *BasecoinApp) initBaseApp(db dbm.DB) {
+	bapp := baseapp.NewBaseApp(appName, db)
 	app.BaseApp = bapp
 	app.router = bapp.Router()
 	app.initBaseAppTxDecoder()
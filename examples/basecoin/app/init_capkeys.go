@@ -4,7 +4,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-// initCapKeys, initBaseApp, initStores, initHandlers.
+// initCapKeys, initBaseApp, initStores, initHandlers, initGenesis.
 func (app *BasecoinApp) initCapKeys() {
 
 	// All top-level capabilities keys
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"encoding/json"
+
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/examples/basecoin/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// initCapKeys, initBaseApp, initStores, initHandlers, initGenesis.
+func (app *BasecoinApp) initGenesis() {
+	app.BaseApp.SetInitChainer(app.initChainer)
+}
+
+// Sets the genesis accounts, denoms and consensus params from the app_state of genesis.json.
+func (app *BasecoinApp) initChainer(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
+	stateJSON := req.AppStateBytes
+	if len(stateJSON) == 0 {
+		return abci.ResponseInitChain{}
+	}
+
+	genesisState := new(types.GenesisState)
+	err := json.Unmarshal(stateJSON, genesisState)
+	if err != nil {
+		panic(err) // TODO: InitChain can't return an error yet.
+	}
+
+	for _, gacc := range genesisState.Accounts {
+		acc, err := gacc.ToAccount()
+		if err != nil {
+			panic(err) // TODO: InitChain can't return an error yet.
+		}
+		acc.SetAccountNumber(app.accountMapper.GetNextAccountNumber(ctx))
+		app.accountMapper.SetAccount(ctx, acc)
+	}
+
+	for _, meta := range genesisState.Denoms {
+		app.denomMapper.SetDenomMetadata(ctx, meta)
+	}
+
+	if genesisState.ConsensusParams != nil {
+		app.StoreConsensusParams(ctx, *genesisState.ConsensusParams)
+	}
+	return abci.ResponseInitChain{}
+}
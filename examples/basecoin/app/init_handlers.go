@@ -6,25 +6,34 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/sketchy"
 )
 
-// initCapKeys, initBaseApp, initStores, initHandlers.
+// initCapKeys, initBaseApp, initStores, initHandlers, initGenesis.
 func (app *BasecoinApp) initHandlers() {
+	app.initCodespaces()
 	app.initDefaultAnteHandler()
 	app.initRouterHandlers()
 }
 
+func (app *BasecoinApp) initCodespaces() {
+
+	// The codespaces of all modules with errors must be
+	// registered here.  Registering one twice panics.
+	app.BaseApp.RegisterCodespace(bank.DefaultCodespace)
+}
+
 func (app *BasecoinApp) initDefaultAnteHandler() {
 
 	// Deducts fee from payer.
 	// Verifies signatures and nonces.
 	// Sets Signers to ctx.
 	app.BaseApp.SetDefaultAnteHandler(
-		auth.NewAnteHandler(app.accountMapper))
+		auth.NewAnteHandler(app.accountMapper, app.feePool))
 }
 
 func (app *BasecoinApp) initRouterHandlers() {
 
 	// All handlers must be added here.
-	// The order matters.
+	// A route receives all Msgs whose type it prefixes,
+	// e.g. "bank" receives "bank/send" and "bank/issue".
 	app.router.AddRoute("bank", bank.NewHandler(app.accountMapper))
 	app.router.AddRoute("sketchy", sketchy.NewHandler())
 }
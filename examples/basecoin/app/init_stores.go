@@ -4,12 +4,15 @@ import (
 	"github.com/cosmos/cosmos-sdk/examples/basecoin/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 )
 
-// initCapKeys, initBaseApp, initStores, initHandlers.
+// initCapKeys, initBaseApp, initStores, initHandlers, initGenesis.
 func (app *BasecoinApp) initStores() {
 	app.mountStores()
 	app.initAccountMapper()
+	app.initFeePool()
+	app.initDenomMapper()
 }
 
 // Initialize root stores.
@@ -32,7 +35,18 @@ func (app *BasecoinApp) initAccountMapper() {
 	// implement those interfaces, here.
 	cdc := accountMapper.WireCodec()
 	auth.RegisterWireBaseAccount(cdc)
+	cdc.RegisterConcrete(&types.AppAccount{}, "basecoin/AppAccount", nil)
 
 	// Make accountMapper's WireCodec() inaccessible.
 	app.accountMapper = accountMapper.Seal()
 }
+
+// Initialize the FeePool.
+func (app *BasecoinApp) initFeePool() {
+	app.feePool = auth.NewFeePool(app.capKeyMainStore)
+}
+
+// Initialize the DenomMapper.
+func (app *BasecoinApp) initDenomMapper() {
+	app.denomMapper = bank.NewDenomMapper(app.capKeyMainStore)
+}
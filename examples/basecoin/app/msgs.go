@@ -1,6 +1,7 @@
 package app
 
 import (
+	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	crypto "github.com/tendermint/go-crypto"
 	wire "github.com/tendermint/go-wire"
@@ -15,6 +16,9 @@ func makeTxCodec() (cdc *wire.Codec) {
 	// Register crypto.[PubKey,PrivKey,Signature] types.
 	crypto.RegisterWire(cdc)
 
+	// Register auth.[PubKeyMultisigThreshold,Multisignature] types.
+	auth.RegisterWireMultisig(cdc)
+
 	// Register bank.[SendMsg,IssueMsg] types.
 	bank.RegisterWire(cdc)
 
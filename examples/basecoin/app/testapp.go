@@ -2,6 +2,7 @@ package app
 
 import (
 	bam "github.com/cosmos/cosmos-sdk/baseapp"
+	dbm "github.com/tendermint/tmlibs/db"
 )
 
 type testBasecoinApp struct {
@@ -10,7 +11,14 @@ type testBasecoinApp struct {
 }
 
 func newTestBasecoinApp() *testBasecoinApp {
-	app := NewBasecoinApp()
+	return newTestBasecoinAppWithDB(dbm.NewMemDB())
+}
+
+func newTestBasecoinAppWithDB(db dbm.DB) *testBasecoinApp {
+	app, err := NewBasecoinApp(db)
+	if err != nil {
+		panic(err)
+	}
 	tba := &testBasecoinApp{
 		BasecoinApp: app,
 	}
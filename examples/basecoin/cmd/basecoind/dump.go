@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/cosmos/cosmos-sdk/examples/basecoin/app"
+)
+
+const flagHeight = "height"
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print the accounts and balances at a height as JSON",
+	Long: `Print the accounts and balances at a height as JSON.
+
+The data directory is opened read-only, so basecoind must not be running.`,
+	RunE: dumpCmdRun,
+}
+
+func init() {
+	dumpCmd.Flags().Int64(flagHeight, 0, "Height of the state to dump (0 for the latest)")
+	basecoindCmd.AddCommand(dumpCmd)
+}
+
+func dumpCmdRun(cmd *cobra.Command, args []string) error {
+	height, err := cmd.Flags().GetInt64(flagHeight)
+	if err != nil {
+		return err
+	}
+	home, err := cmd.Flags().GetString(flagHome)
+	if err != nil {
+		return err
+	}
+
+	db, err := openReadOnlyDB("basecoin", filepath.Join(home, "data"))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	dump, err := dumpState(db, height)
+	if err != nil {
+		return err
+	}
+	bz, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bz))
+	return nil
+}
+
+// dumpState returns the state of db at height, or at the latest
+// height if it's 0.
+func dumpState(db dbm.DB, height int64) (app.StateDump, error) {
+	bapp, err := app.NewBasecoinApp(db)
+	if err != nil {
+		return app.StateDump{}, err
+	}
+	if height != 0 {
+		if err := bapp.LoadHeight(height); err != nil {
+			return app.StateDump{}, err
+		}
+	}
+	return bapp.DumpState()
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	abci "github.com/tendermint/abci/types"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/cosmos/cosmos-sdk/examples/basecoin/app"
+	"github.com/cosmos/cosmos-sdk/examples/basecoin/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// writeLevelDB writes the keys of src into a new LevelDB at
+// dir/name.db, like dbm.NewGoLevelDB(name, dir) would have.
+func writeLevelDB(t *testing.T, src dbm.DB, name, dir string) {
+	db, err := leveldb.OpenFile(filepath.Join(dir, name+".db"), nil)
+	require.Nil(t, err)
+	iter := src.Iterator(nil, nil)
+	for ; iter.Valid(); iter.Next() {
+		require.Nil(t, db.Put(iter.Key(), iter.Value(), nil))
+	}
+	iter.Close()
+	require.Nil(t, db.Close())
+}
+
+// readFiles returns the contents of the files in dir, by path.
+func readFiles(t *testing.T, dir string) map[string][]byte {
+	files := make(map[string][]byte)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		bz, err := ioutil.ReadFile(path)
+		files[path] = bz
+		return err
+	})
+	require.Nil(t, err)
+	return files
+}
+
+func TestDumpReadOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "basecoind")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// A chain with a genesis account at height 1.
+	memDB := dbm.NewMemDB()
+	bapp, err := app.NewBasecoinApp(memDB)
+	require.Nil(t, err)
+	addr := sdk.Address([]byte("input"))
+	stateBytes, err := json.Marshal(types.GenesisState{
+		Accounts: []*types.GenesisAccount{
+			{Name: "alice", Address: addr, Coins: sdk.Coins{sdk.NewCoin("atom", 10)}},
+		},
+	})
+	require.Nil(t, err)
+	bapp.InitChain(abci.RequestInitChain{AppStateBytes: stateBytes})
+	bapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: "test-chain", Height: 1}})
+	bapp.EndBlock(abci.RequestEndBlock{})
+	bapp.Commit()
+	writeLevelDB(t, memDB, "basecoin", dir)
+	before := readFiles(t, dir)
+
+	db, err := openReadOnlyDB("basecoin", dir)
+	require.Nil(t, err)
+	dump, err := dumpState(db, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), dump.Height)
+	if assert.Len(t, dump.Accounts, 1) {
+		assert.Equal(t, addr, dump.Accounts[0].Address)
+	}
+	assert.Panics(t, func() { db.Set([]byte("key"), []byte("value")) })
+	db.Close()
+
+	// Not a byte of the data directory changed.
+	assert.Equal(t, before, readFiles(t, dir))
+}
+
+func TestDumpBadDB(t *testing.T) {
+	// A db whose latest commit is missing.
+	db := dbm.NewMemDB()
+	bapp, err := app.NewBasecoinApp(db)
+	require.Nil(t, err)
+	bapp.InitChain(abci.RequestInitChain{})
+	bapp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{ChainID: "test-chain", Height: 1}})
+	bapp.EndBlock(abci.RequestEndBlock{})
+	bapp.Commit()
+	db.Delete([]byte("s/1"))
+
+	// Loading it errors, rather than exiting.
+	_, err = dumpState(db, 0)
+	assert.NotNil(t, err)
+	_, err = dumpState(db, 1)
+	assert.NotNil(t, err)
+}
+
+func TestReadOnlyDBIterator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "basecoind")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	memDB := dbm.NewMemDB()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		memDB.Set([]byte(key), []byte("v"+key))
+	}
+	writeLevelDB(t, memDB, "test", dir)
+	db, err := openReadOnlyDB("test", dir)
+	require.Nil(t, err)
+	defer db.Close()
+
+	keys := func(iter dbm.Iterator) (res []string) {
+		defer iter.Close()
+		for ; iter.Valid(); iter.Next() {
+			res = append(res, string(iter.Key()))
+		}
+		return res
+	}
+
+	// Iterators have the domains of the other dbm.DBs.
+	domains := [][2][]byte{
+		{nil, nil},
+		{[]byte("b"), nil},
+		{nil, []byte("c")},
+		{[]byte("b"), []byte("d")},
+		{[]byte("c"), []byte("a")},
+	}
+	for _, d := range domains {
+		assert.Equal(t, keys(memDB.Iterator(d[0], d[1])), keys(db.Iterator(d[0], d[1])), "%q", d)
+		assert.Equal(t, keys(memDB.ReverseIterator(d[0], d[1])), keys(db.ReverseIterator(d[0], d[1])), "%q", d)
+	}
+	assert.Equal(t, []byte("vb"), db.Get([]byte("b")))
+	assert.Nil(t, db.Get([]byte("e")))
+}
@@ -2,8 +2,70 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/cosmos/cosmos-sdk/examples/basecoin/app"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	flagHome  = "home"
+	flagTrace = "trace"
 )
 
+var basecoindCmd = &cobra.Command{
+	Use:   "basecoind",
+	Short: "Basecoin ABCI application daemon",
+}
+
+var startCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Run the Basecoin ABCI server",
+	RunE:  startCmdRun,
+}
+
+func init() {
+	basecoindCmd.PersistentFlags().String(flagHome, os.ExpandEnv("$HOME/.basecoind"), "Directory for the application data")
+	startCmd.Flags().Bool(flagTrace, false, "Include error traces in ABCI logs (nondeterministic, for debugging only)")
+	basecoindCmd.AddCommand(startCmd)
+}
+
+// Opens the application database in <home>/data.
+func openDB(cmd *cobra.Command) (dbm.DB, error) {
+	home, err := cmd.Flags().GetString(flagHome)
+	if err != nil {
+		return nil, err
+	}
+	return dbm.NewGoLevelDB("basecoin", filepath.Join(home, "data"))
+}
+
+func startCmdRun(cmd *cobra.Command, args []string) error {
+	trace, err := cmd.Flags().GetBool(flagTrace)
+	if err != nil {
+		return err
+	}
+	sdk.SetErrorDebug(trace)
+
+	db, err := openDB(cmd)
+	if err != nil {
+		return err
+	}
+	bapp, err := app.NewBasecoinApp(db)
+	if err != nil {
+		return err
+	}
+	bapp.RunForever()
+	return nil
+}
+
 func main() {
-	fmt.Println("TODO: move examples/basecoin/main.go here and refactor")
+	if err := basecoindCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// readOnlyDB is a dbm.DB over a LevelDB opened read-only, so that
+// LevelDB itself doesn't rewrite its journal or manifest.  Writes
+// panic.
+type readOnlyDB struct {
+	db *leveldb.DB
+}
+
+var _ dbm.DB = readOnlyDB{}
+
+// openReadOnlyDB opens the LevelDB that dbm.NewGoLevelDB(name, dir)
+// opens, read-only.
+func openReadOnlyDB(name, dir string) (readOnlyDB, error) {
+	path := filepath.Join(dir, name+".db")
+	db, err := leveldb.OpenFile(path, &opt.Options{
+		ReadOnly:       true,
+		ErrorIfMissing: true,
+	})
+	if err != nil {
+		return readOnlyDB{}, err
+	}
+	return readOnlyDB{db}, nil
+}
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) Get(key []byte) []byte {
+	value, err := rdb.db.Get(key, nil)
+	if err == errors.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) Has(key []byte) bool {
+	return rdb.Get(key) != nil
+}
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) Set([]byte, []byte) { panic("read-only db") }
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) SetSync([]byte, []byte) { panic("read-only db") }
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) Delete([]byte) { panic("read-only db") }
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) DeleteSync([]byte) { panic("read-only db") }
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) NewBatch() dbm.Batch { panic("read-only db") }
+
+// Implements dbm.DB.
+// It iterates from start, inclusive, up to end, exclusive.
+func (rdb readOnlyDB) Iterator(start, end []byte) dbm.Iterator {
+	source := rdb.db.NewIterator(&util.Range{Start: start, Limit: end}, nil)
+	source.First()
+	return &readOnlyIterator{source: source, start: start, end: end}
+}
+
+// Implements dbm.DB.
+// It iterates from start, inclusive, down to end, exclusive.
+func (rdb readOnlyDB) ReverseIterator(start, end []byte) dbm.Iterator {
+	var limit []byte
+	if start != nil {
+		limit = append(append([]byte{}, start...), 0x00)
+	}
+	source := rdb.db.NewIterator(&util.Range{Start: end, Limit: limit}, nil)
+	source.Last()
+	return &readOnlyIterator{source: source, start: start, end: end, reverse: true}
+}
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) Close() {
+	rdb.db.Close() // nolint: errcheck
+}
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) Print() {
+	iter := rdb.Iterator(nil, nil)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", iter.Key(), iter.Value())
+	}
+}
+
+// Implements dbm.DB.
+func (rdb readOnlyDB) Stats() map[string]string {
+	stats := make(map[string]string)
+	for _, key := range []string{"leveldb.stats", "leveldb.sstables"} {
+		if value, err := rdb.db.GetProperty(key); err == nil {
+			stats[key] = value
+		}
+	}
+	return stats
+}
+
+//----------------------------------------
+// readOnlyIterator
+
+type readOnlyIterator struct {
+	source     iterator.Iterator
+	start, end []byte
+	reverse    bool
+}
+
+// Implements dbm.Iterator.
+func (it *readOnlyIterator) Domain() (start, end []byte) {
+	return it.start, it.end
+}
+
+// Implements dbm.Iterator.
+// In reverse, the source includes end, which is excluded here.
+func (it *readOnlyIterator) Valid() bool {
+	if !it.source.Valid() {
+		return false
+	}
+	return !(it.reverse && it.end != nil && bytes.Equal(it.source.Key(), it.end))
+}
+
+// Implements dbm.Iterator.
+func (it *readOnlyIterator) Next() {
+	if !it.Valid() {
+		panic("readOnlyIterator is invalid")
+	}
+	if it.reverse {
+		it.source.Prev()
+	} else {
+		it.source.Next()
+	}
+}
+
+// Implements dbm.Iterator.
+// LevelDB reuses its buffers, so the key is copied.
+func (it *readOnlyIterator) Key() []byte {
+	if !it.Valid() {
+		panic("readOnlyIterator is invalid")
+	}
+	return append([]byte{}, it.source.Key()...)
+}
+
+// Implements dbm.Iterator.
+func (it *readOnlyIterator) Value() []byte {
+	if !it.Valid() {
+		panic("readOnlyIterator is invalid")
+	}
+	return append([]byte{}, it.source.Value()...)
+}
+
+// Implements dbm.Iterator.
+func (it *readOnlyIterator) Close() {
+	it.source.Release()
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"errors"
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 )
@@ -25,3 +28,87 @@ func (acc AppAccount) GetName() string {
 func (acc *AppAccount) SetName(name string) {
 	acc.Name = name
 }
+
+//----------------------------------------
+// Genesis
+
+// GenesisState is the app_state of genesis.json.
+type GenesisState struct {
+	Accounts []*GenesisAccount   `json:"accounts"`
+	Denoms   []sdk.DenomMetadata `json:"denoms"`
+
+	// ConsensusParams are optional, and default to no limits.
+	ConsensusParams *sdk.ConsensusParams `json:"consensus_params,omitempty"`
+}
+
+// GenesisAccount doesn't need a pubkey or sequence, as these are set
+// when the account is first used.
+//
+// With a VestingEndTime, its coins vest until then: linearly from
+// VestingStartTime if it's set, else all at once.  Times are unix
+// times in seconds.  The InitChainer's block time is the Unix epoch,
+// so they aren't checked against the genesis time.
+type GenesisAccount struct {
+	Name    string      `json:"name"`
+	Address sdk.Address `json:"address"`
+	Coins   sdk.Coins   `json:"coins"`
+
+	VestingStartTime int64 `json:"vesting_start_time,omitempty"`
+	VestingEndTime   int64 `json:"vesting_end_time,omitempty"`
+}
+
+func NewGenesisAccount(aa *AppAccount) *GenesisAccount {
+	return &GenesisAccount{
+		Name:    aa.Name,
+		Address: aa.Address,
+		Coins:   aa.Coins,
+	}
+}
+
+// ToAppAccount converts a GenesisAccount to an AppAccount.
+func (ga *GenesisAccount) ToAppAccount() (acc *AppAccount, err error) {
+	if len(ga.Address) == 0 {
+		return nil, errors.New("genesis account has no address")
+	}
+	if !ga.Coins.IsValid() || !ga.Coins.IsNotNegative() {
+		return nil, fmt.Errorf("genesis account %v has invalid coins %v", ga.Address, ga.Coins)
+	}
+	baseAcc := auth.NewBaseAccountWithAddress(ga.Address)
+	baseAcc.Coins = ga.Coins
+	return &AppAccount{
+		BaseAccount: baseAcc,
+		Name:        ga.Name,
+	}, nil
+}
+
+// ToAccount converts a GenesisAccount to an AppAccount, or to an
+// auth.ContinuousVestingAccount or auth.DelayedVestingAccount if it
+// vests.  Vesting accounts have no name, so a vesting GenesisAccount
+// can't have one.
+func (ga *GenesisAccount) ToAccount() (sdk.Account, error) {
+	if ga.VestingEndTime != 0 && ga.Name != "" {
+		return nil, fmt.Errorf("genesis account %v is vesting, so it can't have a name", ga.Address)
+	}
+	if ga.VestingStartTime < 0 || ga.VestingEndTime < 0 {
+		return nil, fmt.Errorf("genesis account %v has negative vesting times", ga.Address)
+	}
+	if ga.VestingEndTime == 0 {
+		if ga.VestingStartTime != 0 {
+			return nil, fmt.Errorf("genesis account %v has a vesting start time but no end time", ga.Address)
+		}
+		return ga.ToAppAccount()
+	}
+	if ga.VestingEndTime <= ga.VestingStartTime {
+		return nil, fmt.Errorf("genesis account %v vesting end time %d is not after its start time %d",
+			ga.Address, ga.VestingEndTime, ga.VestingStartTime)
+	}
+
+	appAcc, err := ga.ToAppAccount()
+	if err != nil {
+		return nil, err
+	}
+	if ga.VestingStartTime == 0 {
+		return auth.NewDelayedVestingAccount(ga.Address, appAcc.Coins, ga.VestingEndTime), nil
+	}
+	return auth.NewContinuousVestingAccount(ga.Address, appAcc.Coins, ga.VestingStartTime, ga.VestingEndTime), nil
+}
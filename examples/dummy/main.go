@@ -6,6 +6,7 @@ import (
 
 	"github.com/tendermint/abci/server"
 	cmn "github.com/tendermint/tmlibs/common"
+	dbm "github.com/tendermint/tmlibs/db"
 
 	bam "github.com/cosmos/cosmos-sdk/baseapp"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -16,8 +17,15 @@ func main() {
 	// Capabilities key to access the main KVStore.
 	var capKeyMainStore = sdk.NewKVStoreKey("main")
 
+	// Create the database for the Merkle trees.
+	db, err := dbm.NewGoLevelDB("dummy", "data")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	// Create BaseApp.
-	var baseApp = bam.NewBaseApp("dummy")
+	var baseApp = bam.NewBaseApp("dummy", db)
 
 	// Set mounts for BaseApp's MultiStore.
 	baseApp.MountStore(capKeyMainStore, sdk.StoreTypeIAVL)
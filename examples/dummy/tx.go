@@ -2,9 +2,9 @@ package main
 
 import (
 	"bytes"
+	"math"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	crypto "github.com/tendermint/go-crypto"
 )
 
 // An sdk.Tx which is its own sdk.Msg.
@@ -31,8 +31,8 @@ func (tx dummyTx) Type() string {
 	return "dummy"
 }
 
-func (tx dummyTx) GetMsg() sdk.Msg {
-	return tx
+func (tx dummyTx) GetMsgs() []sdk.Msg {
+	return []sdk.Msg{tx}
 }
 
 func (tx dummyTx) GetSignBytes() []byte {
@@ -44,7 +44,7 @@ func (tx dummyTx) ValidateBasic() sdk.Error {
 	return nil
 }
 
-func (tx dummyTx) GetSigners() []crypto.Address {
+func (tx dummyTx) GetSigners() []sdk.Address {
 	return nil
 }
 
@@ -52,10 +52,14 @@ func (tx dummyTx) GetSignatures() []sdk.StdSignature {
 	return nil
 }
 
-func (tx dummyTx) GetFeePayer() crypto.Address {
+func (tx dummyTx) GetFeePayer() sdk.Address {
 	return nil
 }
 
+func (tx dummyTx) GetFee() sdk.StdFee {
+	return sdk.NewStdFee(math.MaxInt64)
+}
+
 func decodeTx(txBytes []byte) (sdk.Tx, sdk.Error) {
 	var tx sdk.Tx
 
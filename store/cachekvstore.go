@@ -134,6 +134,11 @@ func (ci *cacheKVStore) ReverseIterator(start, end []byte) Iterator {
 	return ci.iterator(start, end, false)
 }
 
+// Implements KVStore.
+func (ci *cacheKVStore) Gas(meter GasMeter) KVStore {
+	return NewGasKVStore(meter, ci)
+}
+
 func (ci *cacheKVStore) iterator(start, end []byte, ascending bool) Iterator {
 	var parent, cache Iterator
 	if ascending {
@@ -0,0 +1,158 @@
+package store
+
+// Gas costs of KVStore accesses.
+// TODO: Make these configurable, and benchmark them.
+// Keys are charged per byte like values, as they may be derived from
+// unbounded user input, e.g. addresses.
+const (
+	HasCost              = 10
+	DeleteCost           = 10
+	ReadCostFlat         = 10
+	ReadCostPerByte      = 1
+	WriteCostFlat        = 10
+	WriteCostPerByte     = 10
+	IteratorCostFlat     = 10
+	IteratorNextCostFlat = 10
+	KeyCostFlat          = 5
+	ValueCostFlat        = 10
+	ValueCostPerByte     = 1
+)
+
+// gasKVStore applies gas tracking to an underlying KVStore.
+type gasKVStore struct {
+	gasMeter GasMeter
+	parent   KVStore
+}
+
+var _ KVStore = &gasKVStore{}
+
+// NewGasKVStore returns a KVStore that charges gasMeter for every
+// access to parent.
+func NewGasKVStore(gasMeter GasMeter, parent KVStore) *gasKVStore {
+	kvs := &gasKVStore{
+		gasMeter: gasMeter,
+		parent:   parent,
+	}
+	return kvs
+}
+
+// Implements Store.
+func (gi *gasKVStore) GetStoreType() StoreType {
+	return gi.parent.GetStoreType()
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Get(key []byte) (value []byte) {
+	gi.gasMeter.ConsumeGas(ReadCostFlat, "ReadFlat")
+	// TODO overflow-safe math?
+	gi.gasMeter.ConsumeGas(ReadCostPerByte*Gas(len(key)), "ReadKeyPerByte")
+	value = gi.parent.Get(key)
+	gi.gasMeter.ConsumeGas(ReadCostPerByte*Gas(len(value)), "ReadPerByte")
+	return value
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Set(key []byte, value []byte) {
+	gi.gasMeter.ConsumeGas(WriteCostFlat, "WriteFlat")
+	// TODO overflow-safe math?
+	gi.gasMeter.ConsumeGas(WriteCostPerByte*Gas(len(key)), "WriteKeyPerByte")
+	gi.gasMeter.ConsumeGas(WriteCostPerByte*Gas(len(value)), "WritePerByte")
+	gi.parent.Set(key, value)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Has(key []byte) bool {
+	gi.gasMeter.ConsumeGas(HasCost, "Has")
+	gi.gasMeter.ConsumeGas(ReadCostPerByte*Gas(len(key)), "ReadKeyPerByte")
+	return gi.parent.Has(key)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Delete(key []byte) {
+	gi.gasMeter.ConsumeGas(DeleteCost, "Delete")
+	gi.gasMeter.ConsumeGas(WriteCostPerByte*Gas(len(key)), "WriteKeyPerByte")
+	gi.parent.Delete(key)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Iterator(start, end []byte) Iterator {
+	return gi.iterator(start, end, true)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) ReverseIterator(start, end []byte) Iterator {
+	return gi.iterator(start, end, false)
+}
+
+// Implements KVStore.
+func (gi *gasKVStore) Gas(meter GasMeter) KVStore {
+	return NewGasKVStore(meter, gi)
+}
+
+// Implements CacheWrapper.
+func (gi *gasKVStore) CacheWrap() CacheWrap {
+	panic("you cannot CacheWrap a GasKVStore")
+}
+
+func (gi *gasKVStore) iterator(start, end []byte, ascending bool) Iterator {
+	gi.gasMeter.ConsumeGas(IteratorCostFlat, "IteratorFlat")
+	var parent Iterator
+	if ascending {
+		parent = gi.parent.Iterator(start, end)
+	} else {
+		parent = gi.parent.ReverseIterator(start, end)
+	}
+	return newGasIterator(gi.gasMeter, parent)
+}
+
+//----------------------------------------
+
+// gasIterator charges for each step, and for each key and value it
+// returns.
+type gasIterator struct {
+	gasMeter GasMeter
+	parent   Iterator
+}
+
+func newGasIterator(gasMeter GasMeter, parent Iterator) Iterator {
+	return &gasIterator{
+		gasMeter: gasMeter,
+		parent:   parent,
+	}
+}
+
+// Implements Iterator.
+func (g *gasIterator) Domain() (start []byte, end []byte) {
+	return g.parent.Domain()
+}
+
+// Implements Iterator.
+func (g *gasIterator) Valid() bool {
+	return g.parent.Valid()
+}
+
+// Implements Iterator.
+func (g *gasIterator) Next() {
+	g.gasMeter.ConsumeGas(IteratorNextCostFlat, "IteratorNextFlat")
+	g.parent.Next()
+}
+
+// Implements Iterator.
+func (g *gasIterator) Key() (key []byte) {
+	g.gasMeter.ConsumeGas(KeyCostFlat, "KeyFlat")
+	key = g.parent.Key()
+	return key
+}
+
+// Implements Iterator.
+func (g *gasIterator) Value() (value []byte) {
+	value = g.parent.Value()
+	g.gasMeter.ConsumeGas(ValueCostFlat, "ValueFlat")
+	g.gasMeter.ConsumeGas(ValueCostPerByte*Gas(len(value)), "ValuePerByte")
+	return value
+}
+
+// Implements Iterator.
+func (g *gasIterator) Close() {
+	g.parent.Close()
+}
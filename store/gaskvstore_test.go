@@ -0,0 +1,132 @@
+package store
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+func newGasKVStore() KVStore {
+	meter := sdk.NewGasMeter(1000)
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	return NewGasKVStore(meter, mem)
+}
+
+func TestGasKVStoreBasic(t *testing.T) {
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	meter := sdk.NewGasMeter(1000)
+	st := NewGasKVStore(meter, mem)
+
+	require.Empty(t, st.Get(keyFmt(1)), "Expected `key1` to be empty")
+	st.Set(keyFmt(1), valFmt(1))
+	require.Equal(t, valFmt(1), st.Get(keyFmt(1)))
+	st.Delete(keyFmt(1))
+	require.Empty(t, st.Get(keyFmt(1)), "Expected `key1` to be empty")
+
+	// Keys have 11 bytes and values 13: two empty reads, a write, a
+	// read and a delete.
+	assert.Equal(t, Gas(21+250+34+120+21), meter.GasConsumed())
+}
+
+func TestGasKVStoreIterator(t *testing.T) {
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	meter := sdk.NewGasMeter(1000)
+	st := NewGasKVStore(meter, mem)
+	st.Set(keyFmt(1), valFmt(1))
+	st.Set(keyFmt(2), valFmt(2))
+	assert.Equal(t, Gas(500), meter.GasConsumed())
+
+	iterator := st.Iterator(nil, nil)
+	defer iterator.Close()
+	for i := 1; iterator.Valid(); i++ {
+		assert.Equal(t, keyFmt(i), iterator.Key())
+		assert.Equal(t, valFmt(i), iterator.Value())
+		iterator.Next()
+	}
+
+	// The iterator costs 10, and each item 5 for the key, 23 for the
+	// value and 10 for the step.
+	assert.Equal(t, Gas(500+10+2*38), meter.GasConsumed())
+}
+
+func TestGasKVStoreOutOfGas(t *testing.T) {
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	meter := sdk.NewGasMeter(100)
+	st := NewGasKVStore(meter, mem)
+
+	// A write of 11+13 bytes costs 250.
+	assert.Panics(t, func() { st.Set(keyFmt(1), valFmt(1)) }, "Expected out-of-gas")
+	assert.Equal(t, Gas(100), meter.GasConsumed())
+	assert.Nil(t, mem.Get(keyFmt(1)), "Expected write to be aborted")
+}
+
+func TestGasKVStoreNested(t *testing.T) {
+	st := newGasKVStore()
+	meter := sdk.NewGasMeter(1000)
+	nested := st.Gas(meter)
+
+	// Both meters are charged.
+	nested.Set(keyFmt(1), valFmt(1))
+	assert.Equal(t, Gas(250), meter.GasConsumed())
+	assert.Equal(t, valFmt(1), st.Get(keyFmt(1)))
+}
+
+func TestGasKVStoreKeyCost(t *testing.T) {
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	longKey := make([]byte, 100)
+
+	// Every access to a key is charged per byte of the key.
+	cases := []struct {
+		access func(st KVStore, key []byte)
+		gas    func(keyLen int) Gas
+	}{
+		{func(st KVStore, key []byte) { st.Get(key) }, func(n int) Gas { return Gas(10 + n) }},
+		{func(st KVStore, key []byte) { st.Has(key) }, func(n int) Gas { return Gas(10 + n) }},
+		{func(st KVStore, key []byte) { st.Set(key, nil) }, func(n int) Gas { return Gas(10 + 10*n) }},
+		{func(st KVStore, key []byte) { st.Delete(key) }, func(n int) Gas { return Gas(10 + 10*n) }},
+	}
+	for i, tc := range cases {
+		for _, key := range [][]byte{keyFmt(1), longKey} {
+			meter := sdk.NewGasMeter(10000)
+			tc.access(NewGasKVStore(meter, mem), key)
+			assert.Equal(t, tc.gas(len(key)), meter.GasConsumed(), "case %d, key %X", i, key)
+		}
+	}
+}
+
+func TestGasKVStoreDelete(t *testing.T) {
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	mem.Set(keyFmt(1), valFmt(1))
+	meter := sdk.NewGasMeter(100)
+	st := NewGasKVStore(meter, mem)
+
+	// A delete of an 11 byte key costs 120.
+	assert.Panics(t, func() { st.Delete(keyFmt(1)) }, "Expected out-of-gas")
+	assert.Equal(t, valFmt(1), mem.Get(keyFmt(1)), "Expected delete to be aborted")
+}
+
+func TestGasKVStoreIteratorNext(t *testing.T) {
+	mem := dbStoreAdapter{dbm.NewMemDB()}
+	for i := 0; i < 100; i++ {
+		mem.Set(keyFmt(i), valFmt(i))
+	}
+	meter := sdk.NewGasMeter(500)
+	st := NewGasKVStore(meter, mem)
+
+	// Creating iterators isn't free.
+	st.Iterator(nil, nil).Close()
+	st.ReverseIterator(nil, nil).Close()
+	assert.Equal(t, Gas(20), meter.GasConsumed())
+
+	// Nor is walking the store without reading keys or values.
+	assert.Panics(t, func() {
+		iterator := st.Iterator(nil, nil)
+		defer iterator.Close()
+		for ; iterator.Valid(); iterator.Next() {
+		}
+	}, "Expected out-of-gas")
+	assert.Equal(t, Gas(500), meter.GasConsumed())
+}
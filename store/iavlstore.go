@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sync"
 
+	abci "github.com/tendermint/abci/types"
 	"github.com/tendermint/iavl"
 	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
@@ -18,7 +19,6 @@ const (
 
 func LoadIAVLStore(db dbm.DB, id CommitID) (CommitStore, error) {
 	tree := iavl.NewVersionedTree(db, defaultIAVLCacheSize)
-	fmt.Println("LoadIAVLStore Version ", id.Version)
 	err := tree.LoadVersion(id.Version)
 	if err != nil {
 		return nil, err
@@ -31,6 +31,7 @@ func LoadIAVLStore(db dbm.DB, id CommitID) (CommitStore, error) {
 
 var _ KVStore = (*iavlStore)(nil)
 var _ CommitStore = (*iavlStore)(nil)
+var _ Queryable = (*iavlStore)(nil)
 
 // iavlStore Implements KVStore and CommitStore.
 type iavlStore struct {
@@ -123,6 +124,52 @@ func (st *iavlStore) ReverseIterator(start, end []byte) Iterator {
 	return newIAVLIterator(st.tree.Tree(), start, end, false)
 }
 
+// Implements KVStore.
+func (st *iavlStore) Gas(meter GasMeter) KVStore {
+	return NewGasKVStore(meter, st)
+}
+
+// Implements Queryable.
+// The only supported path is "/key", with the key in req.Data.
+// A zero req.Height queries the latest committed version.
+func (st *iavlStore) Query(req abci.RequestQuery) (res abci.ResponseQuery) {
+	if len(req.Data) == 0 {
+		msg := "Query cannot be zero length"
+		return sdk.ErrTxParse(msg).QueryResult()
+	}
+
+	tree := st.tree
+	height := req.Height
+	if height == 0 {
+		height = tree.Version64()
+	}
+	if !tree.VersionExists(height) {
+		msg := fmt.Sprintf("Version %v does not exist", height)
+		return sdk.ErrUnknownRequest(msg).QueryResult()
+	}
+	res.Height = height
+
+	switch req.Path {
+	case "/key":
+		key := req.Data // Data holds the key bytes
+		res.Key = key
+		if req.Prove {
+			value, proof, err := tree.GetVersionedWithProof(key, height)
+			if err != nil {
+				return sdk.ErrInternal(err.Error()).QueryResult()
+			}
+			res.Value = value
+			res.Proof = proof.Bytes()
+		} else {
+			_, res.Value = tree.GetVersioned(key, height)
+		}
+	default:
+		msg := fmt.Sprintf("Unexpected Query path: %v", req.Path)
+		return sdk.ErrUnknownRequest(msg).QueryResult()
+	}
+	return
+}
+
 //----------------------------------------
 
 // Implements Iterator.
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	abci "github.com/tendermint/abci/types"
 	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
 
@@ -79,3 +80,45 @@ func TestIAVLIterator(t *testing.T) {
 		i += 1
 	}
 }
+
+func TestIAVLStoreQuery(t *testing.T) {
+	db := dbm.NewMemDB()
+	tree, _ := newTree(t, db)
+	iavlStore := newIAVLStore(tree, numHistory)
+
+	key := []byte("hello")
+	v1 := []byte(treeData["hello"])
+	v2 := []byte("notgoodbye")
+
+	// Overwrite the key in version 2.
+	iavlStore.Set(key, v2)
+	cid := iavlStore.Commit()
+	assert.Equal(t, int64(2), cid.Version)
+
+	// Latest version is used by default.
+	res := iavlStore.Query(abci.RequestQuery{Path: "/key", Data: key})
+	assert.Equal(t, uint32(0), res.Code, res.Log)
+	assert.Equal(t, v2, res.Value)
+	assert.Equal(t, cid.Version, res.Height)
+	assert.Nil(t, res.Proof)
+
+	// Old versions are still queryable.
+	res = iavlStore.Query(abci.RequestQuery{Path: "/key", Data: key, Height: 1})
+	assert.Equal(t, uint32(0), res.Code, res.Log)
+	assert.Equal(t, v1, res.Value)
+	assert.Equal(t, int64(1), res.Height)
+
+	// Proofs are returned on request.
+	res = iavlStore.Query(abci.RequestQuery{Path: "/key", Data: key, Prove: true})
+	assert.Equal(t, uint32(0), res.Code, res.Log)
+	assert.Equal(t, v2, res.Value)
+	assert.NotEmpty(t, res.Proof)
+
+	// Unknown versions, paths and empty keys are rejected.
+	res = iavlStore.Query(abci.RequestQuery{Path: "/key", Data: key, Height: 5})
+	assert.NotEqual(t, uint32(0), res.Code)
+	res = iavlStore.Query(abci.RequestQuery{Path: "/foo", Data: key})
+	assert.NotEqual(t, uint32(0), res.Code)
+	res = iavlStore.Query(abci.RequestQuery{Path: "/key"})
+	assert.NotEqual(t, uint32(0), res.Code)
+}
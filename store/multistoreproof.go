@@ -0,0 +1,68 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/iavl"
+)
+
+// MultiStoreProof links a proof from one of the substores of a
+// rootMultiStore to the rootMultiStore commit hash (i.e. the app hash).
+//
+// It carries every storeInfo of the committed version, so a client can
+// recompute the commit hash, and then check the substore proof against
+// the commit hash recorded for StoreName.
+type MultiStoreProof struct {
+	StoreName  string
+	StoreInfos []storeInfo
+	StoreProof []byte // an iavl.KeyProof
+}
+
+func buildMultiStoreProof(storeProof []byte, storeName string, storeInfos []storeInfo) []byte {
+	proof := MultiStoreProof{
+		StoreName:  storeName,
+		StoreInfos: storeInfos,
+		StoreProof: storeProof,
+	}
+	bz, err := cdc.MarshalBinary(proof)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// ReadMultiStoreProof decodes the proof returned by rootMultiStore.Query.
+func ReadMultiStoreProof(bz []byte) (proof MultiStoreProof, err error) {
+	err = cdc.UnmarshalBinary(bz, &proof)
+	return
+}
+
+// Verify checks that the substore proof proves value for key (a nil
+// value proves absence), and that the substore is committed to by
+// appHash.
+func (proof MultiStoreProof) Verify(key, value []byte, appHash []byte) error {
+	cInfo := commitInfo{StoreInfos: proof.StoreInfos}
+	if !bytes.Equal(cInfo.Hash(), appHash) {
+		return fmt.Errorf("MultiStoreProof: commit hash %X doesn't match app hash %X", cInfo.Hash(), appHash)
+	}
+
+	var storeHash []byte
+	var found bool
+	for _, si := range proof.StoreInfos {
+		if si.Name == proof.StoreName {
+			storeHash = si.Core.CommitID.Hash
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("MultiStoreProof: no store named %s", proof.StoreName)
+	}
+
+	keyProof, err := iavl.ReadKeyProof(proof.StoreProof)
+	if err != nil {
+		return fmt.Errorf("MultiStoreProof: failed to read store proof: %v", err)
+	}
+	return keyProof.Verify(key, value, storeHash)
+}
@@ -0,0 +1,206 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// prefixDB is the view of the keys of a dbm.DB with a prefix, which
+// is stripped.  It lets many stores share a dbm.DB without their keys
+// colliding, e.g. the "r/<version>" roots of IAVL trees.
+// Implements dbm.DB.
+type prefixDB struct {
+	db     dbm.DB
+	prefix []byte
+}
+
+var _ dbm.DB = prefixDB{}
+
+// NewPrefixDB returns the view of the keys of db with prefix.
+func NewPrefixDB(db dbm.DB, prefix []byte) dbm.DB {
+	return prefixDB{
+		db:     db,
+		prefix: prefix,
+	}
+}
+
+func (pdb prefixDB) prefixed(key []byte) []byte {
+	return append(append([]byte{}, pdb.prefix...), key...)
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) Get(key []byte) []byte {
+	return pdb.db.Get(pdb.prefixed(key))
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) Has(key []byte) bool {
+	return pdb.db.Has(pdb.prefixed(key))
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) Set(key, value []byte) {
+	pdb.db.Set(pdb.prefixed(key), value)
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) SetSync(key, value []byte) {
+	pdb.db.SetSync(pdb.prefixed(key), value)
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) Delete(key []byte) {
+	pdb.db.Delete(pdb.prefixed(key))
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) DeleteSync(key []byte) {
+	pdb.db.DeleteSync(pdb.prefixed(key))
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) Iterator(start, end []byte) Iterator {
+	pstart := pdb.prefix
+	if start != nil {
+		pstart = pdb.prefixed(start)
+	}
+	pend := sdk.PrefixEndBytes(pdb.prefix)
+	if end != nil {
+		pend = pdb.prefixed(end)
+	}
+	return newPrefixIterator(pdb.prefix, start, end, pdb.db.Iterator(pstart, pend))
+}
+
+// Implements dbm.DB.
+// Without an end, the parent iterates past the prefix, which the
+// prefixIterator cuts off.
+func (pdb prefixDB) ReverseIterator(start, end []byte) Iterator {
+	pstart := sdk.PrefixEndBytes(pdb.prefix)
+	if start != nil {
+		pstart = pdb.prefixed(start)
+	}
+	var pend []byte
+	if end != nil {
+		pend = pdb.prefixed(end)
+	}
+	return newPrefixIterator(pdb.prefix, start, end, pdb.db.ReverseIterator(pstart, pend))
+}
+
+// Implements dbm.DB.
+// The parent dbm.DB is shared, so it isn't closed.
+func (pdb prefixDB) Close() {}
+
+// Implements dbm.DB.
+func (pdb prefixDB) NewBatch() dbm.Batch {
+	return prefixBatch{pdb, pdb.db.NewBatch()}
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) Print() {
+	fmt.Printf("prefix: %X\n", pdb.prefix)
+	iter := pdb.Iterator(nil, nil)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", iter.Key(), iter.Value())
+	}
+}
+
+// Implements dbm.DB.
+func (pdb prefixDB) Stats() map[string]string {
+	stats := pdb.db.Stats()
+	stats["prefixdb.prefix"] = fmt.Sprintf("%X", pdb.prefix)
+	return stats
+}
+
+//----------------------------------------
+// prefixBatch
+
+type prefixBatch struct {
+	pdb   prefixDB
+	batch dbm.Batch
+}
+
+func (pb prefixBatch) Set(key, value []byte) {
+	pb.batch.Set(pb.pdb.prefixed(key), value)
+}
+
+func (pb prefixBatch) Delete(key []byte) {
+	pb.batch.Delete(pb.pdb.prefixed(key))
+}
+
+func (pb prefixBatch) Write() {
+	pb.batch.Write()
+}
+
+//----------------------------------------
+// prefixIterator
+
+// prefixIterator strips the prefix of the keys of its parent, and is
+// invalid from the first key without the prefix on.
+type prefixIterator struct {
+	prefix     []byte
+	start, end []byte
+	parent     Iterator
+	valid      bool
+}
+
+var _ Iterator = (*prefixIterator)(nil)
+
+func newPrefixIterator(prefix, start, end []byte, parent Iterator) *prefixIterator {
+	// A reverse parent may start past the prefix.
+	for parent.Valid() && !bytes.HasPrefix(parent.Key(), prefix) &&
+		bytes.Compare(parent.Key(), prefix) > 0 {
+		parent.Next()
+	}
+	return &prefixIterator{
+		prefix: prefix,
+		start:  start,
+		end:    end,
+		parent: parent,
+		valid:  parent.Valid() && bytes.HasPrefix(parent.Key(), prefix),
+	}
+}
+
+// Implements Iterator.
+func (pi *prefixIterator) Domain() (start, end []byte) {
+	return pi.start, pi.end
+}
+
+// Implements Iterator.
+func (pi *prefixIterator) Valid() bool {
+	return pi.valid
+}
+
+// Implements Iterator.
+func (pi *prefixIterator) Next() {
+	if !pi.valid {
+		panic("prefixIterator is invalid")
+	}
+	pi.parent.Next()
+	pi.valid = pi.parent.Valid() && bytes.HasPrefix(pi.parent.Key(), pi.prefix)
+}
+
+// Implements Iterator.
+func (pi *prefixIterator) Key() []byte {
+	if !pi.valid {
+		panic("prefixIterator is invalid")
+	}
+	return pi.parent.Key()[len(pi.prefix):]
+}
+
+// Implements Iterator.
+func (pi *prefixIterator) Value() []byte {
+	if !pi.valid {
+		panic("prefixIterator is invalid")
+	}
+	return pi.parent.Value()
+}
+
+// Implements Iterator.
+func (pi *prefixIterator) Close() {
+	pi.parent.Close()
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+func iterKeys(iter Iterator) []string {
+	defer iter.Close()
+	keys := []string{}
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	return keys
+}
+
+func TestPrefixDB(t *testing.T) {
+	db := dbm.NewMemDB()
+	db.Set([]byte("a"), []byte("outside"))
+	db.Set([]byte("p/"), []byte("empty key"))
+	db.Set([]byte("p0"), []byte("past the prefix"))
+	db.Set([]byte("q"), []byte("outside"))
+	pdb := NewPrefixDB(db, []byte("p/"))
+
+	pdb.Set([]byte("b"), []byte("vb"))
+	pdb.Set([]byte("c"), []byte("vc"))
+	batch := pdb.NewBatch()
+	batch.Set([]byte("d"), []byte("vd"))
+	batch.Delete([]byte("c"))
+	batch.Write()
+
+	assert.Equal(t, []byte("vb"), pdb.Get([]byte("b")))
+	assert.Equal(t, []byte("vb"), db.Get([]byte("p/b")))
+	assert.False(t, pdb.Has([]byte("c")))
+	assert.True(t, pdb.Has([]byte("d")))
+	assert.Nil(t, pdb.Get([]byte("a")))
+
+	assert.Equal(t, []string{"", "b", "d"}, iterKeys(pdb.Iterator(nil, nil)))
+	assert.Equal(t, []string{"b"}, iterKeys(pdb.Iterator([]byte("a"), []byte("c"))))
+	assert.Equal(t, []string{"d", "b", ""}, iterKeys(pdb.ReverseIterator(nil, nil)))
+	assert.Equal(t, []string{"b"}, iterKeys(pdb.ReverseIterator([]byte("c"), []byte("a"))))
+	assert.Equal(t, []string{"d", "b"}, iterKeys(pdb.ReverseIterator(nil, []byte(""))))
+	assert.Equal(t, []string{"b", ""}, iterKeys(pdb.ReverseIterator([]byte("c"), nil)))
+	assert.Equal(t, []string{"d"}, iterKeys(pdb.ReverseIterator([]byte("e"), []byte("b"))))
+
+	pdb.Delete([]byte("b"))
+	assert.Nil(t, db.Get([]byte("p/b")))
+	assert.Equal(t, []byte("outside"), db.Get([]byte("a")))
+}
+
+func TestPrefixDBMaxPrefix(t *testing.T) {
+	// The prefix has no end, so the parent is iterated to its end.
+	db := dbm.NewMemDB()
+	db.Set([]byte{0xfe}, []byte("before"))
+	db.Set([]byte{0xff, 0xfe}, []byte("before"))
+	pdb := NewPrefixDB(db, []byte{0xff, 0xff})
+	pdb.Set([]byte("a"), []byte("va"))
+	pdb.Set([]byte("b"), []byte("vb"))
+
+	assert.Equal(t, []string{"a", "b"}, iterKeys(pdb.Iterator(nil, nil)))
+	assert.Equal(t, []string{"b"}, iterKeys(pdb.Iterator([]byte("b"), nil)))
+	assert.Equal(t, []string{"a"}, iterKeys(pdb.Iterator(nil, []byte("b"))))
+	assert.Equal(t, []string{"b", "a"}, iterKeys(pdb.ReverseIterator(nil, nil)))
+	assert.Equal(t, []string{"b"}, iterKeys(pdb.ReverseIterator(nil, []byte("a"))))
+	assert.Equal(t, []string{"a"}, iterKeys(pdb.ReverseIterator([]byte("a"), nil)))
+}
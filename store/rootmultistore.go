@@ -2,7 +2,9 @@ package store
 
 import (
 	"fmt"
+	"strings"
 
+	abci "github.com/tendermint/abci/types"
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/merkle"
 	"golang.org/x/crypto/ripemd160"
@@ -12,7 +14,20 @@ import (
 
 const (
 	latestVersionKey = "s/latest"
-	commitInfoKeyFmt = "s/%d" // s/<version>
+	layoutKey        = "s/layout"
+	commitInfoKeyFmt = "s/%d"    // s/<version>
+	storePrefixFmt   = "s/k:%s/" // s/k:<name>/
+)
+
+// Layouts of the keys of the stores mounted without a db.
+const (
+	// Every store keeps its keys unprefixed in the multistore's db, so
+	// the "r/<version>" roots of IAVL stores collide.  Only dbs first
+	// committed before layoutPrefixed have it.
+	layoutShared int64 = iota
+
+	// Each store keeps its keys under its own prefix "s/k:<name>/".
+	layoutPrefixed
 )
 
 // rootMultiStore is composed of many CommitStores.
@@ -21,12 +36,14 @@ const (
 // Implements MultiStore.
 type rootMultiStore struct {
 	db           dbm.DB
+	layout       int64
 	lastCommitID CommitID
 	storesParams map[StoreKey]storeParams
 	stores       map[StoreKey]CommitStore
 }
 
 var _ CommitMultiStore = (*rootMultiStore)(nil)
+var _ Queryable = (*rootMultiStore)(nil)
 
 func NewCommitMultiStore(db dbm.DB) *rootMultiStore {
 	return &rootMultiStore{
@@ -47,7 +64,7 @@ func (rs *rootMultiStore) MountStoreWithDB(key StoreKey, typ StoreType, db dbm.D
 		panic("MountIAVLStore() key cannot be nil")
 	}
 	if _, ok := rs.storesParams[key]; ok {
-		panic(fmt.Sprintf("rootMultiStore duplicate store key", key))
+		panic(fmt.Sprintf("rootMultiStore duplicate store key %v", key))
 	}
 	rs.storesParams[key] = storeParams{
 		db:  db,
@@ -69,11 +86,14 @@ func (rs *rootMultiStore) LoadLatestVersion() error {
 // Implements CommitMultiStore.
 func (rs *rootMultiStore) LoadVersion(ver int64) error {
 
+	// Keep the layout the db was created with.
+	rs.layout = getLayout(rs.db)
+
 	// Special logic for version 0
 	if ver == 0 {
 		for key, storeParams := range rs.storesParams {
 			id := CommitID{}
-			store, err := rs.loadCommitStoreFromParams(id, storeParams)
+			store, err := rs.loadCommitStoreFromParams(key, id, storeParams)
 			if err != nil {
 				return fmt.Errorf("Failed to load rootMultiStore: %v", err)
 			}
@@ -96,7 +116,7 @@ func (rs *rootMultiStore) LoadVersion(ver int64) error {
 	for _, storeInfo := range cInfo.StoreInfos {
 		key, commitID := rs.nameToKey(storeInfo.Name), storeInfo.Core.CommitID
 		storeParams := rs.storesParams[key]
-		store, err := rs.loadCommitStoreFromParams(commitID, storeParams)
+		store, err := rs.loadCommitStoreFromParams(key, commitID, storeParams)
 		if err != nil {
 			return fmt.Errorf("Failed to load rootMultiStore: %v", err)
 		}
@@ -135,6 +155,7 @@ func (rs *rootMultiStore) Commit() CommitID {
 	batch := rs.db.NewBatch()
 	setCommitInfo(batch, version, commitInfo)
 	setLatestVersion(batch, version)
+	setLayout(batch, rs.layout)
 	batch.Write()
 
 	// Prepare for next version.
@@ -169,12 +190,82 @@ func (rs *rootMultiStore) GetKVStore(key StoreKey) KVStore {
 	return rs.stores[key].(KVStore)
 }
 
+//----------------------------------------
+// +Queryable
+
+// Implements Queryable.
+// The path must be "/<storeName>/<subpath>"; the rest of the request
+// is passed on to the named substore with the path set to subpath.
+// If req.Prove is set, the substore proof is wrapped in a
+// MultiStoreProof that links it to the commit hash of the same version.
+func (rs *rootMultiStore) Query(req abci.RequestQuery) abci.ResponseQuery {
+	storeName, subpath, err := parsePath(req.Path)
+	if err != nil {
+		return err.QueryResult()
+	}
+
+	store := rs.getStoreByName(storeName)
+	if store == nil {
+		msg := fmt.Sprintf("no such store: %s", storeName)
+		return sdk.ErrUnknownRequest(msg).QueryResult()
+	}
+	queryable, ok := store.(Queryable)
+	if !ok {
+		msg := fmt.Sprintf("store %s doesn't support queries", storeName)
+		return sdk.ErrUnknownRequest(msg).QueryResult()
+	}
+
+	// Pin the height, so the substore and commitInfo agree.
+	if req.Height == 0 {
+		req.Height = rs.lastCommitID.Version
+	}
+	req.Path = subpath
+	res := queryable.Query(req)
+	if !req.Prove || sdk.ABCICodeType(res.Code) != sdk.ABCICodeOK {
+		return res
+	}
+
+	cInfo, cErr := getCommitInfo(rs.db, res.Height)
+	if cErr != nil {
+		return sdk.ErrInternal(cErr.Error()).QueryResult()
+	}
+	res.Proof = buildMultiStoreProof(res.Proof, storeName, cInfo.StoreInfos)
+	return res
+}
+
+// Returns nil if no mounted store has the given name.
+func (rs *rootMultiStore) getStoreByName(name string) Store {
+	for key, store := range rs.stores {
+		if key.Name() == name {
+			return store
+		}
+	}
+	return nil
+}
+
+// parsePath expects a path of the form "/<storeName>[/<subpath>]".
+// The returned subpath keeps its leading slash, and may be empty.
+func parsePath(path string) (storeName string, subpath string, err sdk.Error) {
+	if !strings.HasPrefix(path, "/") {
+		err = sdk.ErrUnknownRequest(fmt.Sprintf("invalid path: %s", path))
+		return
+	}
+	paths := strings.SplitN(path[1:], "/", 2)
+	storeName = paths[0]
+	if len(paths) == 2 {
+		subpath = "/" + paths[1]
+	}
+	return
+}
+
 //----------------------------------------
 
-func (rs *rootMultiStore) loadCommitStoreFromParams(id CommitID, params storeParams) (store CommitStore, err error) {
+func (rs *rootMultiStore) loadCommitStoreFromParams(key StoreKey, id CommitID, params storeParams) (store CommitStore, err error) {
 	db := rs.db
 	if params.db != nil {
 		db = params.db
+	} else if rs.layout == layoutPrefixed {
+		db = NewPrefixDB(rs.db, []byte(fmt.Sprintf(storePrefixFmt, key.Name())))
 	}
 	switch params.typ {
 	case sdk.StoreTypeMulti:
@@ -287,6 +378,30 @@ func setLatestVersion(batch dbm.Batch, version int64) {
 	batch.Set([]byte(latestVersionKey), latestBytes)
 }
 
+// Gets the layout of the db.  Dbs committed before layouts were
+// recorded have layoutShared, and new ones get layoutPrefixed.
+func getLayout(db dbm.DB) int64 {
+	layoutBytes := db.Get([]byte(layoutKey))
+	if layoutBytes == nil {
+		if getLatestVersion(db) > 0 {
+			return layoutShared
+		}
+		return layoutPrefixed
+	}
+	var layout int64
+	err := cdc.UnmarshalBinary(layoutBytes, &layout)
+	if err != nil {
+		panic(err)
+	}
+	return layout
+}
+
+// Set the layout.
+func setLayout(batch dbm.Batch, layout int64) {
+	layoutBytes, _ := cdc.MarshalBinary(layout) // Does not error
+	batch.Set([]byte(layoutKey), layoutBytes)
+}
+
 // Commits each store and returns a new commitInfo.
 func commitStores(version int64, storeMap map[StoreKey]CommitStore) commitInfo {
 	storeInfos := make([]storeInfo, 0, len(storeMap))
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	abci "github.com/tendermint/abci/types"
 	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/merkle"
 
@@ -62,6 +63,110 @@ func TestMultistoreCommitLoad(t *testing.T) {
 	checkStore(t, store, commitID, commitID)
 }
 
+func TestMultistoreLayout(t *testing.T) {
+	key1, key2 := sdk.NewKVStoreKey("store1"), sdk.NewKVStoreKey("store2")
+	newMultiStore := func(db dbm.DB, keys ...StoreKey) *rootMultiStore {
+		store := NewCommitMultiStore(db)
+		for _, key := range keys {
+			store.MountStoreWithDB(key, sdk.StoreTypeIAVL, nil)
+		}
+		err := store.LoadLatestVersion()
+		assert.Nil(t, err)
+		return store
+	}
+
+	// A new db gets a prefix per store, so IAVL stores can share it.
+	db := dbm.NewMemDB()
+	store := newMultiStore(db, key1, key2)
+	store.GetKVStore(key1).Set([]byte("key"), []byte("value1"))
+	store.GetKVStore(key2).Set([]byte("key"), []byte("value2"))
+	store.Commit()
+	store = newMultiStore(db, key1, key2)
+	assert.Equal(t, layoutPrefixed, store.layout)
+	assert.Equal(t, []byte("value1"), store.GetKVStore(key1).Get([]byte("key")))
+	assert.Equal(t, []byte("value2"), store.GetKVStore(key2).Get([]byte("key")))
+	iter := db.Iterator([]byte("s/k:store2/"), []byte("s/k:store20"))
+	assert.True(t, iter.Valid())
+	iter.Close()
+
+	// A db committed before the layouts keeps its unprefixed keys.
+	db = dbm.NewMemDB()
+	store = NewCommitMultiStore(db)
+	store.MountStoreWithDB(key1, sdk.StoreTypeIAVL, db)
+	err := store.LoadLatestVersion()
+	assert.Nil(t, err)
+	store.GetKVStore(key1).Set([]byte("key"), []byte("value1"))
+	store.Commit()
+	db.Delete([]byte(layoutKey))
+	store = newMultiStore(db, key1)
+	assert.Equal(t, layoutShared, store.layout)
+	assert.Equal(t, []byte("value1"), store.GetKVStore(key1).Get([]byte("key")))
+	store.Commit()
+	store = newMultiStore(db, key1)
+	assert.Equal(t, layoutShared, store.layout)
+	assert.Equal(t, []byte("value1"), store.GetKVStore(key1).Get([]byte("key")))
+}
+
+func TestMultistoreQuery(t *testing.T) {
+	// Each substore gets its own db, as they will hold data.
+	multi := NewCommitMultiStore(dbm.NewMemDB())
+	multi.MountStoreWithDB(
+		sdk.NewKVStoreKey("store1"), sdk.StoreTypeIAVL, dbm.NewMemDB())
+	multi.MountStoreWithDB(
+		sdk.NewKVStoreKey("store2"), sdk.StoreTypeIAVL, dbm.NewMemDB())
+	err := multi.LoadLatestVersion()
+	assert.Nil(t, err)
+
+	k, v := []byte("wind"), []byte("blows")
+	k2, v2 := []byte("water"), []byte("flows")
+
+	// Set and commit data in one store.
+	store1 := multi.getStoreByName("store1").(KVStore)
+	store1.Set(k, v)
+	store2 := multi.getStoreByName("store2").(KVStore)
+	store2.Set(k2, v2)
+	cid := multi.Commit()
+
+	// Test bad path.
+	query := abci.RequestQuery{Path: "/key", Data: k, Height: cid.Version}
+	qres := multi.Query(query)
+	assert.NotEqual(t, uint32(0), qres.Code)
+
+	query.Path = "h897fy32890rf63296r92"
+	qres = multi.Query(query)
+	assert.NotEqual(t, uint32(0), qres.Code)
+
+	// Test invalid store name.
+	query.Path = "/garbage/key"
+	qres = multi.Query(query)
+	assert.NotEqual(t, uint32(0), qres.Code)
+
+	// Test valid query with data.
+	query.Path = "/store1/key"
+	qres = multi.Query(query)
+	assert.Equal(t, uint32(0), qres.Code, qres.Log)
+	assert.Equal(t, v, qres.Value)
+
+	// Test valid but empty query.
+	query.Path = "/store2/key"
+	qres = multi.Query(query)
+	assert.Equal(t, uint32(0), qres.Code, qres.Log)
+	assert.Nil(t, qres.Value)
+
+	// Test the proof links the value to the commit hash.
+	query = abci.RequestQuery{Path: "/store2/key", Data: k2, Prove: true}
+	qres = multi.Query(query)
+	assert.Equal(t, uint32(0), qres.Code, qres.Log)
+	assert.Equal(t, v2, qres.Value)
+	assert.Equal(t, cid.Version, qres.Height)
+	proof, err := ReadMultiStoreProof(qres.Proof)
+	assert.Nil(t, err)
+	assert.Equal(t, "store2", proof.StoreName)
+	assert.Nil(t, proof.Verify(k2, v2, cid.Hash))
+	assert.NotNil(t, proof.Verify(k2, []byte("ebbs"), cid.Hash))
+	assert.NotNil(t, proof.Verify(k2, v2, []byte("wronghash")))
+}
+
 //-----------------------------------------------------------------------
 // utils
 
@@ -10,6 +10,7 @@ type MultiStore = types.MultiStore
 type CacheMultiStore = types.CacheMultiStore
 type CommitStore = types.CommitStore
 type Committer = types.Committer
+type Queryable = types.Queryable
 type CommitMultiStore = types.CommitMultiStore
 type KVStore = types.KVStore
 type Iterator = types.Iterator
@@ -19,3 +20,5 @@ type CacheWrap = types.CacheWrap
 type CommitID = types.CommitID
 type StoreKey = types.StoreKey
 type StoreType = types.StoreType
+type GasMeter = types.GasMeter
+type Gas = types.Gas
@@ -0,0 +1,13 @@
+package types
+
+import abci "github.com/tendermint/abci/types"
+
+// InitChainer initializes application state at genesis.
+type InitChainer func(ctx Context, req abci.RequestInitChain) abci.ResponseInitChain
+
+// BeginBlocker runs code before the transactions in a block.
+type BeginBlocker func(ctx Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock
+
+// EndBlocker runs code after the transactions in a block, and may
+// return updates to the validator set.
+type EndBlocker func(ctx Context, req abci.RequestEndBlock) abci.ResponseEndBlock
@@ -4,11 +4,14 @@ import (
 	crypto "github.com/tendermint/go-crypto"
 )
 
-// Account is a standard account using a sequence number for replay protection
-// and a pubkey for authentication.
+// Account is a standard account using an account number and a sequence
+// number for replay protection and a pubkey for authentication.
 type Account interface {
-	GetAddress() crypto.Address
-	SetAddress(crypto.Address) error // errors if already set.
+	GetAddress() Address
+	SetAddress(Address) error // errors if already set.
+
+	GetAccountNumber() int64
+	SetAccountNumber(int64) error
 
 	GetPubKey() crypto.PubKey // can return nil.
 	SetPubKey(crypto.PubKey) error
@@ -26,7 +29,15 @@ type Account interface {
 // AccountMapper stores and retrieves accounts from stores
 // retrieved from the context.
 type AccountMapper interface {
-	NewAccountWithAddress(ctx Context, addr crypto.Address) Account
-	GetAccount(ctx Context, addr crypto.Address) Account
+	// NewAccountWithAddress returns a new account with the next
+	// account number, which it consumes.
+	NewAccountWithAddress(ctx Context, addr Address) Account
+	// GetNextAccountNumber returns and consumes the next account number.
+	GetNextAccountNumber(ctx Context) int64
+	GetAccount(ctx Context, addr Address) Account
 	SetAccount(ctx Context, acc Account)
+	// IterateAccounts calls process on each account, in the order of
+	// their addresses, until process returns true.
+	// CONTRACT: process must not write accounts.
+	IterateAccounts(ctx Context, process func(Account) (stop bool))
 }
@@ -0,0 +1,144 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/btcsuite/btcutil/bech32"
+	crypto "github.com/tendermint/go-crypto"
+)
+
+// Bech32 human-readable prefixes of account addresses and pubkeys.
+// Apps should set them, e.g. in main(), before any address is encoded
+// or decoded.
+var (
+	Bech32PrefixAccAddr = "cosmosaccaddr"
+	Bech32PrefixAccPub  = "cosmosaccpub"
+)
+
+// Address is the address of an account.  It is displayed and
+// JSON encoded in bech32 with the Bech32PrefixAccAddr prefix, which
+// includes a checksum, e.g. "cosmosaccaddr1d9h8qat5e4ehc5".
+type Address []byte
+
+// NewAddress returns the Address of pubKey.
+func NewAddress(pubKey crypto.PubKey) Address {
+	return Address(pubKey.Address())
+}
+
+// Bytes returns the raw address.
+func (addr Address) Bytes() []byte {
+	return addr
+}
+
+// Empty returns true if the address has no bytes.
+func (addr Address) Empty() bool {
+	return len(addr) == 0
+}
+
+// String returns the bech32 encoding of addr, or "" if it's empty.
+func (addr Address) String() string {
+	if addr.Empty() {
+		return ""
+	}
+	str, err := Bech32ifyAcc(addr)
+	if err != nil {
+		panic(err)
+	}
+	return str
+}
+
+// MarshalJSON implements json.Marshaler.
+func (addr Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(addr.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.  It errors if the address
+// is not bech32 with the Bech32PrefixAccAddr prefix.
+func (addr *Address) UnmarshalJSON(bz []byte) error {
+	var str string
+	if err := json.Unmarshal(bz, &str); err != nil {
+		return err
+	}
+	if str == "" {
+		*addr = nil
+		return nil
+	}
+	res, err := GetAccAddressBech32(str)
+	if err != nil {
+		return err
+	}
+	*addr = res
+	return nil
+}
+
+//----------------------------------------
+// Bech32
+
+// Bech32ifyAcc returns the bech32 encoding of an account address.
+func Bech32ifyAcc(addr Address) (string, error) {
+	return bech32ify(Bech32PrefixAccAddr, addr)
+}
+
+// Bech32ifyAccPub returns the bech32 encoding of an account pubkey.
+// It errors if the encoding is too long to decode, e.g. for a multisig
+// pubkey.
+func Bech32ifyAccPub(pubKey crypto.PubKey) (string, error) {
+	return bech32ify(Bech32PrefixAccPub, pubKey.Bytes())
+}
+
+// GetAccAddressBech32 decodes a bech32 account address.
+func GetAccAddressBech32(str string) (Address, error) {
+	bz, err := getFromBech32(str, Bech32PrefixAccAddr)
+	if err != nil {
+		return nil, err
+	}
+	return Address(bz), nil
+}
+
+// GetAccPubKeyBech32 decodes a bech32 account pubkey.
+func GetAccPubKeyBech32(str string) (crypto.PubKey, error) {
+	bz, err := getFromBech32(str, Bech32PrefixAccPub)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.PubKeyFromBytes(bz)
+}
+
+// bech32MaxLen is the maximum length of a bech32 string, which
+// bech32.Decode enforces.
+const bech32MaxLen = 90
+
+func bech32ify(prefix string, bz []byte) (string, error) {
+	conv, err := bech32.ConvertBits(bz, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	str, err := bech32.Encode(prefix, conv)
+	if err != nil {
+		return "", err
+	}
+	if len(str) > bech32MaxLen {
+		return "", fmt.Errorf("%d bytes are too long for bech32", len(bz))
+	}
+	return str, nil
+}
+
+// getFromBech32 decodes str, and errors if its prefix isn't prefix.
+func getFromBech32(str, prefix string) ([]byte, error) {
+	hrp, conv, err := bech32.Decode(str)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != prefix {
+		return nil, fmt.Errorf("invalid bech32 prefix: expected %s, got %s", prefix, hrp)
+	}
+	bz, err := bech32.ConvertBits(conv, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("empty bech32 %s", prefix)
+	}
+	return bz, nil
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	crypto "github.com/tendermint/go-crypto"
+)
+
+func TestAddressBech32(t *testing.T) {
+	addr := Address([]byte("input"))
+	assert.Equal(t, "cosmosaccaddr1d9h8qat5e4ehc5", addr.String())
+
+	res, err := GetAccAddressBech32(addr.String())
+	assert.Nil(t, err)
+	assert.Equal(t, addr, res)
+
+	// Random addresses round-trip.
+	for i := 0; i < 10; i++ {
+		addr := NewAddress(crypto.GenPrivKeyEd25519().PubKey())
+		res, err := GetAccAddressBech32(addr.String())
+		assert.Nil(t, err)
+		assert.Equal(t, addr, res)
+	}
+
+	invalid := []string{
+		"",
+		"cosmosaccaddr1d9h8qat5e4ehc6", // bad checksum
+		"cosmosaccaddr1d9h8qat5e4ehc",  // truncated
+		"696E707574",                   // hex
+		"COSMOSACCADDR1d9h8qat5e4ehc5", // mixed case
+	}
+	for _, str := range invalid {
+		_, err := GetAccAddressBech32(str)
+		assert.NotNil(t, err, "%s", str)
+	}
+
+	// A valid bech32 string with another prefix is rejected.
+	wrongPrefix, err := bech32ify("cosmosvaladdr", addr)
+	assert.Nil(t, err)
+	_, err = GetAccAddressBech32(wrongPrefix)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "invalid bech32 prefix")
+	}
+}
+
+func TestAddressJSON(t *testing.T) {
+	addr := Address([]byte("input"))
+	bz, err := json.Marshal(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, `"cosmosaccaddr1d9h8qat5e4ehc5"`, string(bz))
+
+	var res Address
+	assert.Nil(t, json.Unmarshal(bz, &res))
+	assert.Equal(t, addr, res)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"696E707574"`), &res))
+	pubStr, err := Bech32ifyAccPub(crypto.GenPrivKeyEd25519().PubKey())
+	assert.Nil(t, err)
+	assert.NotNil(t, json.Unmarshal([]byte(`"`+pubStr+`"`), &res), "Expected a pubkey to be rejected")
+
+	// Empty addresses are encoded as "".
+	bz, err = json.Marshal(Address(nil))
+	assert.Nil(t, err)
+	assert.Equal(t, `""`, string(bz))
+}
+
+func TestPubKeyBech32(t *testing.T) {
+	pub := crypto.GenPrivKeyEd25519().PubKey()
+	str, err := Bech32ifyAccPub(pub)
+	assert.Nil(t, err)
+	assert.Equal(t, "cosmosaccpub1", str[:13])
+
+	res, err := GetAccPubKeyBech32(str)
+	assert.Nil(t, err)
+	assert.True(t, pub.Equals(res))
+
+	addrStr, err := Bech32ifyAcc(NewAddress(pub))
+	assert.Nil(t, err)
+	_, err = GetAccPubKeyBech32(addrStr)
+	assert.NotNil(t, err, "Expected an address to be rejected")
+
+	// Encodings that can't be decoded are refused.
+	_, err = bech32ify(Bech32PrefixAccPub, make([]byte, 100))
+	assert.NotNil(t, err)
+}
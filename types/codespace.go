@@ -0,0 +1,31 @@
+package types
+
+import "fmt"
+
+// Codespacer keeps track of the codespaces used by the modules of an
+// app, so that two modules can't use the same one.
+type Codespacer struct {
+	reserved map[CodespaceType]bool
+}
+
+// NewCodespacer returns a Codespacer with CodespaceRoot reserved.
+func NewCodespacer() *Codespacer {
+	cs := &Codespacer{
+		reserved: make(map[CodespaceType]bool),
+	}
+	cs.RegisterCodespace(CodespaceRoot)
+	return cs
+}
+
+// RegisterCodespace reserves codespace, and panics if it is already
+// reserved.  CodespaceType 0 is invalid, so that an unset codespace
+// is never mistaken for a module's.
+func (cs *Codespacer) RegisterCodespace(codespace CodespaceType) {
+	if codespace == 0 {
+		panic("codespace 0 is reserved")
+	}
+	if cs.reserved[codespace] {
+		panic(fmt.Sprintf("codespace %d is already registered", codespace))
+	}
+	cs.reserved[codespace] = true
+}
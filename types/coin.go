@@ -4,14 +4,21 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 )
 
 // Coin hold some amount of one currency
 type Coin struct {
 	Denom  string `json:"denom"`
-	Amount int64  `json:"amount"`
+	Amount Int    `json:"amount"`
+}
+
+// NewCoin returns a Coin of amount denom.
+func NewCoin(denom string, amount int64) Coin {
+	return Coin{
+		Denom:  denom,
+		Amount: NewInt(amount),
+	}
 }
 
 // String provides a human-readable representation of a coin
@@ -21,14 +28,14 @@ func (coin Coin) String() string {
 
 // IsZero returns if this represents no money
 func (coin Coin) IsZero() bool {
-	return coin.Amount == 0
+	return coin.Amount.IsZero()
 }
 
 // IsGTE returns true if they are the same type and the receiver is
 // an equal or greater value
 func (coin Coin) IsGTE(other Coin) bool {
 	return (coin.Denom == other.Denom) &&
-		(coin.Amount >= other.Amount)
+		!coin.Amount.LT(other.Amount)
 }
 
 //----------------------------------------
@@ -55,14 +62,14 @@ func (coins Coins) IsValid() bool {
 	case 0:
 		return true
 	case 1:
-		return coins[0].Amount != 0
+		return !coins[0].Amount.IsZero()
 	default:
 		lowDenom := coins[0].Denom
 		for _, coin := range coins[1:] {
 			if coin.Denom <= lowDenom {
 				return false
 			}
-			if coin.Amount == 0 {
+			if coin.Amount.IsZero() {
 				return false
 			}
 			// we compare each coin against the last denom
@@ -74,6 +81,7 @@ func (coins Coins) IsValid() bool {
 
 // Plus combines two sets of coins
 // CONTRACT: Plus will never return Coins where one Coin has a 0 amount.
+// Plus panics if an amount overflows.
 func (coins Coins) Plus(coinsB Coins) Coins {
 	sum := []Coin{}
 	indexA, indexB := 0, 0
@@ -93,12 +101,13 @@ func (coins Coins) Plus(coinsB Coins) Coins {
 			sum = append(sum, coinA)
 			indexA++
 		case 0:
-			if coinA.Amount+coinB.Amount == 0 {
+			amount := coinA.Amount.Add(coinB.Amount)
+			if amount.IsZero() {
 				// ignore 0 sum coin type
 			} else {
 				sum = append(sum, Coin{
 					Denom:  coinA.Denom,
-					Amount: coinA.Amount + coinB.Amount,
+					Amount: amount,
 				})
 			}
 			indexA++
@@ -116,7 +125,7 @@ func (coins Coins) Negative() Coins {
 	for _, coin := range coins {
 		res = append(res, Coin{
 			Denom:  coin.Denom,
-			Amount: -coin.Amount,
+			Amount: coin.Amount.Neg(),
 		})
 	}
 	return res
@@ -149,7 +158,8 @@ func (coins Coins) IsEqual(coinsB Coins) bool {
 		return false
 	}
 	for i := 0; i < len(coins); i++ {
-		if coins[i] != coinsB[i] {
+		if coins[i].Denom != coinsB[i].Denom ||
+			!coins[i].Amount.Equal(coinsB[i].Amount) {
 			return false
 		}
 	}
@@ -163,7 +173,7 @@ func (coins Coins) IsPositive() bool {
 		return false
 	}
 	for _, coinAmount := range coins {
-		if coinAmount.Amount <= 0 {
+		if coinAmount.Amount.Sign() <= 0 {
 			return false
 		}
 	}
@@ -177,23 +187,24 @@ func (coins Coins) IsNotNegative() bool {
 		return true
 	}
 	for _, coinAmount := range coins {
-		if coinAmount.Amount < 0 {
+		if coinAmount.Amount.Sign() < 0 {
 			return false
 		}
 	}
 	return true
 }
 
-func (coins Coins) AmountOf(denom string) int64 {
+// AmountOf returns the amount of denom in coins, or 0 if there is none.
+func (coins Coins) AmountOf(denom string) Int {
 	switch len(coins) {
 	case 0:
-		return 0
+		return ZeroInt()
 	case 1:
 		coin := coins[0]
 		if coin.Denom == denom {
 			return coin.Amount
 		} else {
-			return 0
+			return ZeroInt()
 		}
 	default:
 		midIdx := len(coins) / 2 // 2:1, 3:1, 4:2
@@ -244,12 +255,13 @@ func ParseCoin(coinStr string) (coin Coin, err error) {
 	}
 	denomStr, amountStr := matches[2], matches[1]
 
-	amount, err := strconv.Atoi(amountStr)
-	if err != nil {
+	amount, ok := NewIntFromString(amountStr)
+	if !ok {
+		err = fmt.Errorf("Invalid coin amount: %s", amountStr)
 		return
 	}
 
-	return Coin{denomStr, int64(amount)}, nil
+	return Coin{denomStr, amount}, nil
 }
 
 // ParseCoins will parse out a list of coins separated by commas.
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,35 +11,35 @@ func TestCoins(t *testing.T) {
 
 	//Define the coins to be used in tests
 	good := Coins{
-		{"GAS", 1},
-		{"MINERAL", 1},
-		{"TREE", 1},
+		NewCoin("GAS", 1),
+		NewCoin("MINERAL", 1),
+		NewCoin("TREE", 1),
 	}
 	neg := good.Negative()
 	sum := good.Plus(neg)
 	empty := Coins{
-		{"GOLD", 0},
+		NewCoin("GOLD", 0),
 	}
 	badSort1 := Coins{
-		{"TREE", 1},
-		{"GAS", 1},
-		{"MINERAL", 1},
+		NewCoin("TREE", 1),
+		NewCoin("GAS", 1),
+		NewCoin("MINERAL", 1),
 	}
 	// both are after the first one, but the second and third are in the wrong order
 	badSort2 := Coins{
-		{"GAS", 1},
-		{"TREE", 1},
-		{"MINERAL", 1},
+		NewCoin("GAS", 1),
+		NewCoin("TREE", 1),
+		NewCoin("MINERAL", 1),
 	}
 	badAmt := Coins{
-		{"GAS", 1},
-		{"TREE", 0},
-		{"MINERAL", 1},
+		NewCoin("GAS", 1),
+		NewCoin("TREE", 0),
+		NewCoin("MINERAL", 1),
 	}
 	dup := Coins{
-		{"GAS", 1},
-		{"GAS", 1},
-		{"MINERAL", 1},
+		NewCoin("GAS", 1),
+		NewCoin("GAS", 1),
+		NewCoin("MINERAL", 1),
 	}
 
 	assert.True(t, good.IsValid(), "Coins are valid")
@@ -61,11 +62,11 @@ func TestPlusCoins(t *testing.T) {
 		inputTwo Coins
 		expected Coins
 	}{
-		{Coins{{"A", 1}, {"B", 1}}, Coins{{"A", 1}, {"B", 1}}, Coins{{"A", 2}, {"B", 2}}},
-		{Coins{{"A", 0}, {"B", 1}}, Coins{{"A", 0}, {"B", 0}}, Coins{{"B", 1}}},
-		{Coins{{"A", 0}, {"B", 0}}, Coins{{"A", 0}, {"B", 0}}, Coins{}},
-		{Coins{{"A", 1}, {"B", 0}}, Coins{{"A", -1}, {"B", 0}}, Coins{}},
-		{Coins{{"A", -1}, {"B", 0}}, Coins{{"A", 0}, {"B", 0}}, Coins{{"A", -1}}},
+		{Coins{NewCoin("A", 1), NewCoin("B", 1)}, Coins{NewCoin("A", 1), NewCoin("B", 1)}, Coins{NewCoin("A", 2), NewCoin("B", 2)}},
+		{Coins{NewCoin("A", 0), NewCoin("B", 1)}, Coins{NewCoin("A", 0), NewCoin("B", 0)}, Coins{NewCoin("B", 1)}},
+		{Coins{NewCoin("A", 0), NewCoin("B", 0)}, Coins{NewCoin("A", 0), NewCoin("B", 0)}, Coins{}},
+		{Coins{NewCoin("A", 1), NewCoin("B", 0)}, Coins{NewCoin("A", -1), NewCoin("B", 0)}, Coins{}},
+		{Coins{NewCoin("A", -1), NewCoin("B", 0)}, Coins{NewCoin("A", 0), NewCoin("B", 0)}, Coins{NewCoin("A", -1)}},
 	}
 
 	for _, tc := range cases {
@@ -84,12 +85,12 @@ func TestParse(t *testing.T) {
 		expected Coins // if valid is true, make sure this is returned
 	}{
 		{"", true, nil},
-		{"1foo", true, Coins{{"foo", 1}}},
-		{"10bar", true, Coins{{"bar", 10}}},
-		{"99bar,1foo", true, Coins{{"bar", 99}, {"foo", 1}}},
-		{"98 bar , 1 foo  ", true, Coins{{"bar", 98}, {"foo", 1}}},
-		{"  55\t \t bling\n", true, Coins{{"bling", 55}}},
-		{"2foo, 97 bar", true, Coins{{"bar", 97}, {"foo", 2}}},
+		{"1foo", true, Coins{NewCoin("foo", 1)}},
+		{"10bar", true, Coins{NewCoin("bar", 10)}},
+		{"99bar,1foo", true, Coins{NewCoin("bar", 99), NewCoin("foo", 1)}},
+		{"98 bar , 1 foo  ", true, Coins{NewCoin("bar", 98), NewCoin("foo", 1)}},
+		{"  55\t \t bling\n", true, Coins{NewCoin("bling", 55)}},
+		{"2foo, 97 bar", true, Coins{NewCoin("bar", 97), NewCoin("foo", 2)}},
 		{"5 mycoin,", false, nil},             // no empty coins in a list
 		{"2 3foo, 97 bar", false, nil},        // 3foo is invalid coin name
 		{"11me coin, 12you coin", false, nil}, // no spaces in coin names
@@ -111,32 +112,32 @@ func TestParse(t *testing.T) {
 func TestSortCoins(t *testing.T) {
 
 	good := Coins{
-		{"GAS", 1},
-		{"MINERAL", 1},
-		{"TREE", 1},
+		NewCoin("GAS", 1),
+		NewCoin("MINERAL", 1),
+		NewCoin("TREE", 1),
 	}
 	empty := Coins{
-		{"GOLD", 0},
+		NewCoin("GOLD", 0),
 	}
 	badSort1 := Coins{
-		{"TREE", 1},
-		{"GAS", 1},
-		{"MINERAL", 1},
+		NewCoin("TREE", 1),
+		NewCoin("GAS", 1),
+		NewCoin("MINERAL", 1),
 	}
 	badSort2 := Coins{ // both are after the first one, but the second and third are in the wrong order
-		{"GAS", 1},
-		{"TREE", 1},
-		{"MINERAL", 1},
+		NewCoin("GAS", 1),
+		NewCoin("TREE", 1),
+		NewCoin("MINERAL", 1),
 	}
 	badAmt := Coins{
-		{"GAS", 1},
-		{"TREE", 0},
-		{"MINERAL", 1},
+		NewCoin("GAS", 1),
+		NewCoin("TREE", 0),
+		NewCoin("MINERAL", 1),
 	}
 	dup := Coins{
-		{"GAS", 1},
-		{"GAS", 1},
-		{"MINERAL", 1},
+		NewCoin("GAS", 1),
+		NewCoin("GAS", 1),
+		NewCoin("MINERAL", 1),
 	}
 
 	cases := []struct {
@@ -162,31 +163,31 @@ func TestAmountOf(t *testing.T) {
 
 	case0 := Coins{}
 	case1 := Coins{
-		{"", 0},
+		NewCoin("", 0),
 	}
 	case2 := Coins{
-		{" ", 0},
+		NewCoin(" ", 0),
 	}
 	case3 := Coins{
-		{"GOLD", 0},
+		NewCoin("GOLD", 0),
 	}
 	case4 := Coins{
-		{"GAS", 1},
-		{"MINERAL", 1},
-		{"TREE", 1},
+		NewCoin("GAS", 1),
+		NewCoin("MINERAL", 1),
+		NewCoin("TREE", 1),
 	}
 	case5 := Coins{
-		{"MINERAL", 1},
-		{"TREE", 1},
+		NewCoin("MINERAL", 1),
+		NewCoin("TREE", 1),
 	}
 	case6 := Coins{
-		{"", 6},
+		NewCoin("", 6),
 	}
 	case7 := Coins{
-		{" ", 7},
+		NewCoin(" ", 7),
 	}
 	case8 := Coins{
-		{"GAS", 8},
+		NewCoin("GAS", 8),
 	}
 
 	cases := []struct {
@@ -209,10 +210,28 @@ func TestAmountOf(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		assert.Equal(t, tc.amountOf, tc.coins.AmountOf(""))
-		assert.Equal(t, tc.amountOf_, tc.coins.AmountOf(" "))
-		assert.Equal(t, tc.amountOfGAS, tc.coins.AmountOf("GAS"))
-		assert.Equal(t, tc.amountOfMINERAL, tc.coins.AmountOf("MINERAL"))
-		assert.Equal(t, tc.amountOfTREE, tc.coins.AmountOf("TREE"))
+		assert.Equal(t, tc.amountOf, tc.coins.AmountOf("").Int64())
+		assert.Equal(t, tc.amountOf_, tc.coins.AmountOf(" ").Int64())
+		assert.Equal(t, tc.amountOfGAS, tc.coins.AmountOf("GAS").Int64())
+		assert.Equal(t, tc.amountOfMINERAL, tc.coins.AmountOf("MINERAL").Int64())
+		assert.Equal(t, tc.amountOfTREE, tc.coins.AmountOf("TREE").Int64())
 	}
 }
+
+func TestLargeCoins(t *testing.T) {
+	amount := "1000000000000000000000000"
+	coins, err := ParseCoins(amount + "atom")
+	assert.Nil(t, err)
+	assert.Equal(t, amount, coins.AmountOf("atom").String())
+
+	sum := coins.Plus(coins)
+	assert.Equal(t, "2000000000000000000000000", sum.AmountOf("atom").String())
+	assert.True(t, sum.IsGTE(coins))
+	assert.False(t, coins.IsGTE(sum))
+	assert.True(t, sum.Minus(coins).IsEqual(coins))
+	assert.Equal(t, "-"+amount, coins.Negative().AmountOf("atom").String())
+
+	// Amounts beyond the maximum Int are rejected.
+	_, err = ParseCoins(strings.Repeat("9", 100) + "atom")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,22 @@
+package types
+
+import "fmt"
+
+// ConsensusParams are the chain-wide limits that handlers may need to
+// respect, e.g. when deciding whether a tx can fit in a block.
+// A value of 0 means unlimited.
+type ConsensusParams struct {
+	MaxBlockGas   int64 `json:"max_block_gas"`
+	MaxBlockBytes int64 `json:"max_block_bytes"`
+}
+
+// ValidateBasic checks that no limit is negative.
+func (params ConsensusParams) ValidateBasic() error {
+	if params.MaxBlockGas < 0 {
+		return fmt.Errorf("negative max_block_gas %d", params.MaxBlockGas)
+	}
+	if params.MaxBlockBytes < 0 {
+		return fmt.Errorf("negative max_block_bytes %d", params.MaxBlockBytes)
+	}
+	return nil
+}
@@ -3,14 +3,14 @@ package types
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
 	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
 )
 
-// TODO: Add a default logger.
-
 /*
 The intent of Context is for it to be an immutable object that can be
 cloned and updated cheaply with WithValue() and passed forward to the
@@ -39,9 +39,14 @@ func NewContext(ms MultiStore, header abci.Header, isCheckTx bool, txBytes []byt
 	c = c.WithMultiStore(ms)
 	c = c.WithBlockHeader(header)
 	c = c.WithBlockHeight(header.Height)
+	c = c.WithBlockTime(time.Unix(header.Time, 0).UTC())
+	c = c.WithConsensusParams(ConsensusParams{})
 	c = c.WithChainID(header.ChainID)
 	c = c.WithIsCheckTx(isCheckTx)
 	c = c.WithTxBytes(txBytes)
+	c = c.WithGasMeter(NewInfiniteGasMeter())
+	c = c.WithLogger(log.NewNopLogger())
+	c = c.WithSkipSigCheck(false)
 	return c
 }
 
@@ -64,8 +69,9 @@ func (c Context) Value(key interface{}) interface{} {
 }
 
 // KVStore fetches a KVStore from the MultiStore.
+// All accesses are charged to the Context's GasMeter.
 func (c Context) KVStore(key StoreKey) KVStore {
-	return c.multiStore().GetKVStore(key)
+	return c.multiStore().GetKVStore(key).Gas(c.GasMeter())
 }
 
 //----------------------------------------
@@ -126,9 +132,14 @@ const (
 	contextKeyMultiStore contextKey = iota
 	contextKeyBlockHeader
 	contextKeyBlockHeight
+	contextKeyBlockTime
+	contextKeyConsensusParams
 	contextKeyChainID
 	contextKeyIsCheckTx
 	contextKeyTxBytes
+	contextKeyGasMeter
+	contextKeyLogger
+	contextKeySkipSigCheck
 )
 
 // NOTE: Do not expose MultiStore.
@@ -146,6 +157,18 @@ func (c Context) BlockHeight() int64 {
 	return c.Value(contextKeyBlockHeight).(int64)
 }
 
+// BlockTime returns the time of the current block, in UTC.
+// In the InitChainer it's the Unix epoch, as RequestInitChain has no
+// genesis time.
+func (c Context) BlockTime() time.Time {
+	return c.Value(contextKeyBlockTime).(time.Time)
+}
+
+// ConsensusParams returns the consensus parameters of the chain.
+func (c Context) ConsensusParams() ConsensusParams {
+	return c.Value(contextKeyConsensusParams).(ConsensusParams)
+}
+
 func (c Context) ChainID() string {
 	return c.Value(contextKeyChainID).(string)
 }
@@ -158,6 +181,22 @@ func (c Context) TxBytes() []byte {
 	return c.Value(contextKeyTxBytes).([]byte)
 }
 
+func (c Context) GasMeter() GasMeter {
+	return c.Value(contextKeyGasMeter).(GasMeter)
+}
+
+// SkipSigCheck is true when simulating a tx without verifying
+// its signatures.
+func (c Context) SkipSigCheck() bool {
+	return c.Value(contextKeySkipSigCheck).(bool)
+}
+
+// Logger returns the logger for the current block or tx.
+// Handlers should scope it, e.g. ctx.Logger().With("module", "x/bank").
+func (c Context) Logger() log.Logger {
+	return c.Value(contextKeyLogger).(log.Logger)
+}
+
 func (c Context) WithMultiStore(ms MultiStore) Context {
 	return c.withValue(contextKeyMultiStore, ms)
 }
@@ -171,6 +210,14 @@ func (c Context) WithBlockHeight(height int64) Context {
 	return c.withValue(contextKeyBlockHeight, height)
 }
 
+func (c Context) WithBlockTime(t time.Time) Context {
+	return c.withValue(contextKeyBlockTime, t)
+}
+
+func (c Context) WithConsensusParams(params ConsensusParams) Context {
+	return c.withValue(contextKeyConsensusParams, params)
+}
+
 func (c Context) WithChainID(chainID string) Context {
 	return c.withValue(contextKeyChainID, chainID)
 }
@@ -183,6 +230,18 @@ func (c Context) WithTxBytes(txBytes []byte) Context {
 	return c.withValue(contextKeyTxBytes, txBytes)
 }
 
+func (c Context) WithGasMeter(meter GasMeter) Context {
+	return c.withValue(contextKeyGasMeter, meter)
+}
+
+func (c Context) WithSkipSigCheck(skip bool) Context {
+	return c.withValue(contextKeySkipSigCheck, skip)
+}
+
+func (c Context) WithLogger(logger log.Logger) Context {
+	return c.withValue(contextKeyLogger, logger)
+}
+
 //----------------------------------------
 // thePast
 
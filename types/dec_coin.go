@@ -0,0 +1,235 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DecCoin holds a decimal amount of one currency, for fractional
+// accounting such as fee distribution.
+type DecCoin struct {
+	Denom  string `json:"denom"`
+	Amount Dec    `json:"amount"`
+}
+
+// NewDecCoin returns a DecCoin with the integer amount of denom.
+func NewDecCoin(denom string, amount int64) DecCoin {
+	return DecCoin{
+		Denom:  denom,
+		Amount: NewDec(amount),
+	}
+}
+
+// NewDecCoinFromCoin converts a Coin to a DecCoin.
+func NewDecCoinFromCoin(coin Coin) DecCoin {
+	return DecCoin{
+		Denom:  coin.Denom,
+		Amount: NewDecFromInt(coin.Amount),
+	}
+}
+
+// String provides a human-readable representation of a coin
+func (coin DecCoin) String() string {
+	return fmt.Sprintf("%v%v", coin.Amount, coin.Denom)
+}
+
+// IsZero returns if this represents no money
+func (coin DecCoin) IsZero() bool {
+	return coin.Amount.IsZero()
+}
+
+//----------------------------------------
+// DecCoins
+
+// DecCoins is a set of DecCoin, one per currency, sorted by denom.
+type DecCoins []DecCoin
+
+// NewDecCoins converts Coins to DecCoins.
+func NewDecCoins(coins Coins) DecCoins {
+	decCoins := make(DecCoins, len(coins))
+	for i, coin := range coins {
+		decCoins[i] = NewDecCoinFromCoin(coin)
+	}
+	return decCoins
+}
+
+func (coins DecCoins) String() string {
+	if len(coins) == 0 {
+		return ""
+	}
+
+	out := ""
+	for _, coin := range coins {
+		out += fmt.Sprintf("%v,", coin.String())
+	}
+	return out[:len(out)-1]
+}
+
+// IsValid asserts the DecCoins are sorted, and don't have 0 amounts
+func (coins DecCoins) IsValid() bool {
+	lowDenom := ""
+	for i, coin := range coins {
+		if i > 0 && coin.Denom <= lowDenom {
+			return false
+		}
+		if coin.Amount.IsZero() {
+			return false
+		}
+		lowDenom = coin.Denom
+	}
+	return true
+}
+
+// Plus combines two sets of coins
+// CONTRACT: Plus will never return DecCoins where one DecCoin has a 0
+// amount.  Plus panics if an amount overflows.
+func (coins DecCoins) Plus(coinsB DecCoins) DecCoins {
+	sum := []DecCoin{}
+	indexA, indexB := 0, 0
+	lenA, lenB := len(coins), len(coinsB)
+	for {
+		if indexA == lenA {
+			if indexB == lenB {
+				return sum
+			}
+			return append(sum, coinsB[indexB:]...)
+		} else if indexB == lenB {
+			return append(sum, coins[indexA:]...)
+		}
+		coinA, coinB := coins[indexA], coinsB[indexB]
+		switch strings.Compare(coinA.Denom, coinB.Denom) {
+		case -1:
+			sum = append(sum, coinA)
+			indexA++
+		case 0:
+			amount := coinA.Amount.Add(coinB.Amount)
+			if !amount.IsZero() {
+				sum = append(sum, DecCoin{
+					Denom:  coinA.Denom,
+					Amount: amount,
+				})
+			}
+			indexA++
+			indexB++
+		case 1:
+			sum = append(sum, coinB)
+			indexB++
+		}
+	}
+}
+
+// Negative returns a set of coins with all amount negative
+func (coins DecCoins) Negative() DecCoins {
+	res := make([]DecCoin, 0, len(coins))
+	for _, coin := range coins {
+		res = append(res, DecCoin{
+			Denom:  coin.Denom,
+			Amount: coin.Amount.Neg(),
+		})
+	}
+	return res
+}
+
+// Minus subtracts a set of coins from another (adds the inverse)
+func (coins DecCoins) Minus(coinsB DecCoins) DecCoins {
+	return coins.Plus(coinsB.Negative())
+}
+
+// MulDec multiplies every amount by d, with banker's rounding.
+// Amounts that round to 0 are removed.
+func (coins DecCoins) MulDec(d Dec) DecCoins {
+	res := DecCoins{}
+	for _, coin := range coins {
+		amount := coin.Amount.Mul(d)
+		if !amount.IsZero() {
+			res = append(res, DecCoin{coin.Denom, amount})
+		}
+	}
+	return res
+}
+
+// QuoDec divides every amount by d, with banker's rounding.
+// Amounts that round to 0 are removed.  It panics if d is 0.
+func (coins DecCoins) QuoDec(d Dec) DecCoins {
+	res := DecCoins{}
+	for _, coin := range coins {
+		amount := coin.Amount.Quo(d)
+		if !amount.IsZero() {
+			res = append(res, DecCoin{coin.Denom, amount})
+		}
+	}
+	return res
+}
+
+// TruncateDecimal splits coins into their integer parts and the
+// remaining fractional change, so that no value is lost:
+// NewDecCoins(truncated).Plus(change) equals coins.
+func (coins DecCoins) TruncateDecimal() (truncated Coins, change DecCoins) {
+	truncated, change = Coins{}, DecCoins{}
+	for _, coin := range coins {
+		intAmount := coin.Amount.TruncateInt()
+		if !intAmount.IsZero() {
+			truncated = append(truncated, Coin{coin.Denom, intAmount})
+		}
+		rem := coin.Amount.Sub(NewDecFromInt(intAmount))
+		if !rem.IsZero() {
+			change = append(change, DecCoin{coin.Denom, rem})
+		}
+	}
+	return truncated, change
+}
+
+// IsZero returns true if there are no coins
+func (coins DecCoins) IsZero() bool {
+	return len(coins) == 0
+}
+
+// IsEqual returns true if the two sets of DecCoins have the same value
+func (coins DecCoins) IsEqual(coinsB DecCoins) bool {
+	if len(coins) != len(coinsB) {
+		return false
+	}
+	for i := 0; i < len(coins); i++ {
+		if coins[i].Denom != coinsB[i].Denom ||
+			!coins[i].Amount.Equal(coinsB[i].Amount) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNotNegative returns true if there is no currency with a negative value
+// (even no coins is true here)
+func (coins DecCoins) IsNotNegative() bool {
+	for _, coin := range coins {
+		if coin.Amount.Sign() < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AmountOf returns the amount of denom in coins, or 0 if there is none.
+func (coins DecCoins) AmountOf(denom string) Dec {
+	i := sort.Search(len(coins), func(i int) bool {
+		return coins[i].Denom >= denom
+	})
+	if i < len(coins) && coins[i].Denom == denom {
+		return coins[i].Amount
+	}
+	return ZeroDec()
+}
+
+//----------------------------------------
+// Sort interface
+
+// nolint
+func (coins DecCoins) Len() int           { return len(coins) }
+func (coins DecCoins) Less(i, j int) bool { return coins[i].Denom < coins[j].Denom }
+func (coins DecCoins) Swap(i, j int)      { coins[i], coins[j] = coins[j], coins[i] }
+
+var _ sort.Interface = DecCoins{}
+
+// Sort is a helper function to sort the set of coins inplace
+func (coins DecCoins) Sort() { sort.Sort(coins) }
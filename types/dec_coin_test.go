@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecCoinsPlusMinus(t *testing.T) {
+	a := DecCoins{{"atom", mustDec(t, "1.5")}, {"eth", mustDec(t, "2")}}
+	b := DecCoins{{"atom", mustDec(t, "0.5")}, {"btc", mustDec(t, "0.1")}}
+
+	sum := a.Plus(b)
+	assert.True(t, sum.IsValid())
+	assert.Equal(t, "2.000000000000000000atom,0.100000000000000000btc,2.000000000000000000eth", sum.String())
+	assert.True(t, sum.Minus(b).IsEqual(a))
+	assert.True(t, a.Minus(a).IsZero())
+	assert.False(t, b.Minus(a).IsNotNegative())
+	assert.Equal(t, "0.100000000000000000", sum.AmountOf("btc").String())
+	assert.True(t, sum.AmountOf("foo").IsZero())
+
+	assert.False(t, DecCoins{{"atom", ZeroDec()}}.IsValid())
+	assert.False(t, DecCoins{{"eth", OneDec()}, {"atom", OneDec()}}.IsValid())
+}
+
+func TestDecCoinsTruncateDecimal(t *testing.T) {
+	// Split a fee of 10atom,1eth three ways.
+	fees := NewDecCoins(Coins{NewCoin("atom", 10), NewCoin("eth", 1)})
+	share := fees.QuoDec(NewDec(3))
+	assert.Equal(t, "3.333333333333333333atom,0.333333333333333333eth", share.String())
+
+	truncated, change := share.TruncateDecimal()
+	assert.True(t, truncated.IsEqual(Coins{NewCoin("atom", 3)}))
+	assert.Equal(t, "0.333333333333333333atom,0.333333333333333333eth", change.String())
+	assert.True(t, NewDecCoins(truncated).Plus(change).IsEqual(share))
+
+	// Nothing is lost when the shares are added back up.
+	total := share.MulDec(NewDec(3))
+	remainder := fees.Minus(total)
+	assert.Equal(t, "0.000000000000000001atom,0.000000000000000001eth", remainder.String())
+	assert.True(t, total.Plus(remainder).IsEqual(fees))
+
+	truncated, change = fees.TruncateDecimal()
+	assert.True(t, truncated.IsEqual(Coins{NewCoin("atom", 10), NewCoin("eth", 1)}))
+	assert.True(t, change.IsZero())
+}
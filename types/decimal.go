@@ -0,0 +1,291 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Precision is the number of decimal places of a Dec.
+const Precision = 18
+
+// decimalPrecisionBits is the number of bits needed to store the
+// fractional part of a Dec, i.e. ceil(log2(10^Precision)).
+const decimalPrecisionBits = 60
+
+// maxDecBits is the maximum bit length of the scaled integer of a
+// Dec.  Arithmetic that would exceed it panics.
+const maxDecBits = maxIntBits + decimalPrecisionBits
+
+var precisionMultiplier = new(big.Int).Exp(big.NewInt(10), big.NewInt(Precision), nil)
+
+// Dec is a signed fixed-point decimal with Precision decimal places,
+// for fractional accounting in consensus code.  It never uses floats.
+// Its zero value is 0.  Decs are immutable: every operation returns a
+// new Dec.
+type Dec struct {
+	i *big.Int // value * 10^Precision
+}
+
+// NewDec returns a Dec with the integer value i.
+func NewDec(i int64) Dec {
+	return NewDecWithPrec(i, 0)
+}
+
+// NewDecWithPrec returns a Dec with the value i * 10^-prec,
+// e.g. NewDecWithPrec(15, 1) is 1.5.
+// It panics if prec is not in [0, Precision].
+func NewDecWithPrec(i, prec int64) Dec {
+	if prec < 0 || prec > Precision {
+		panic(fmt.Sprintf("invalid precision %d", prec))
+	}
+	return Dec{new(big.Int).Mul(big.NewInt(i), precisionMultiplierOf(Precision-prec))}
+}
+
+// NewDecFromInt returns a Dec with the integer value i.
+func NewDecFromInt(i Int) Dec {
+	return checkDec(new(big.Int).Mul(i.get(), precisionMultiplier))
+}
+
+// NewDecFromStr parses a decimal string, e.g. "-12.345".
+// It errors if there are more than Precision decimal places, or if
+// the value is out of range.
+func NewDecFromStr(str string) (Dec, error) {
+	if len(str) == 0 {
+		return Dec{}, fmt.Errorf("decimal string is empty")
+	}
+	neg := false
+	if str[0] == '-' {
+		neg = true
+		str = str[1:]
+	}
+
+	intStr, fracStr := str, ""
+	if idx := strings.IndexByte(str, '.'); idx >= 0 {
+		intStr, fracStr = str[:idx], str[idx+1:]
+		if len(fracStr) == 0 {
+			return Dec{}, fmt.Errorf("invalid decimal %q", str)
+		}
+	}
+	if len(intStr) == 0 {
+		return Dec{}, fmt.Errorf("invalid decimal %q", str)
+	}
+	if len(fracStr) > Precision {
+		return Dec{}, fmt.Errorf("too many decimal places in %q", str)
+	}
+	for _, r := range intStr + fracStr {
+		if r < '0' || r > '9' {
+			return Dec{}, fmt.Errorf("invalid decimal %q", str)
+		}
+	}
+
+	// Pad the fraction to Precision places and parse as an integer.
+	combined := intStr + fracStr + strings.Repeat("0", Precision-len(fracStr))
+	i, ok := new(big.Int).SetString(combined, 10)
+	if !ok {
+		return Dec{}, fmt.Errorf("invalid decimal %q", str)
+	}
+	if i.BitLen() > maxDecBits {
+		return Dec{}, fmt.Errorf("decimal out of range: %q", str)
+	}
+	if neg {
+		i.Neg(i)
+	}
+	return Dec{i}, nil
+}
+
+// ZeroDec returns a Dec with value 0.
+func ZeroDec() Dec { return Dec{new(big.Int)} }
+
+// OneDec returns a Dec with value 1.
+func OneDec() Dec { return Dec{new(big.Int).Set(precisionMultiplier)} }
+
+// precisionMultiplierOf returns 10^prec.
+func precisionMultiplierOf(prec int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(prec), nil)
+}
+
+// checkDec panics if i is out of range.
+func checkDec(i *big.Int) Dec {
+	if i.BitLen() > maxDecBits {
+		panic("Dec overflow")
+	}
+	return Dec{i}
+}
+
+// get returns the scaled big.Int, treating nil as 0.
+// The result must not be modified.
+func (d Dec) get() *big.Int {
+	if d.i == nil {
+		return new(big.Int)
+	}
+	return d.i
+}
+
+// IsZero returns true if the value is 0.
+func (d Dec) IsZero() bool { return d.get().Sign() == 0 }
+
+// Sign returns -1, 0 or +1 according to the sign of the value.
+func (d Dec) Sign() int { return d.get().Sign() }
+
+// Equal returns true if d == d2.
+func (d Dec) Equal(d2 Dec) bool { return d.get().Cmp(d2.get()) == 0 }
+
+// GT returns true if d > d2.
+func (d Dec) GT(d2 Dec) bool { return d.get().Cmp(d2.get()) > 0 }
+
+// GTE returns true if d >= d2.
+func (d Dec) GTE(d2 Dec) bool { return d.get().Cmp(d2.get()) >= 0 }
+
+// LT returns true if d < d2.
+func (d Dec) LT(d2 Dec) bool { return d.get().Cmp(d2.get()) < 0 }
+
+// LTE returns true if d <= d2.
+func (d Dec) LTE(d2 Dec) bool { return d.get().Cmp(d2.get()) <= 0 }
+
+// Neg returns -d.
+func (d Dec) Neg() Dec { return Dec{new(big.Int).Neg(d.get())} }
+
+// Add returns d + d2.  It panics on overflow.
+func (d Dec) Add(d2 Dec) Dec {
+	return checkDec(new(big.Int).Add(d.get(), d2.get()))
+}
+
+// Sub returns d - d2.  It panics on overflow.
+func (d Dec) Sub(d2 Dec) Dec {
+	return checkDec(new(big.Int).Sub(d.get(), d2.get()))
+}
+
+// Mul returns d * d2, rounded to Precision places with banker's
+// rounding.  It panics on overflow.
+func (d Dec) Mul(d2 Dec) Dec {
+	mul := new(big.Int).Mul(d.get(), d2.get())
+	return checkDec(chopPrecisionAndRound(mul))
+}
+
+// MulInt returns d * i.  It panics on overflow.
+func (d Dec) MulInt(i Int) Dec {
+	return checkDec(new(big.Int).Mul(d.get(), i.get()))
+}
+
+// Quo returns d / d2, rounded to Precision places with banker's
+// rounding.  It panics if d2 is 0 or on overflow.
+func (d Dec) Quo(d2 Dec) Dec {
+	if d2.IsZero() {
+		panic("division by zero")
+	}
+	// Multiply by 10^Precision to keep the scale of the quotient.
+	mul := new(big.Int).Mul(d.get(), precisionMultiplier)
+	return checkDec(quoAndRound(mul, d2.get()))
+}
+
+// QuoInt returns d / i, rounded to Precision places with banker's
+// rounding.  It panics if i is 0.
+func (d Dec) QuoInt(i Int) Dec {
+	if i.IsZero() {
+		panic("division by zero")
+	}
+	return Dec{quoAndRound(new(big.Int).Set(d.get()), i.get())}
+}
+
+// RoundInt returns d rounded to an integer with banker's rounding.
+func (d Dec) RoundInt() Int {
+	return checkInt(chopPrecisionAndRound(new(big.Int).Set(d.get())))
+}
+
+// TruncateInt returns the integer part of d, truncated towards zero.
+func (d Dec) TruncateInt() Int {
+	return checkInt(new(big.Int).Quo(d.get(), precisionMultiplier))
+}
+
+// TruncateDec returns the integer part of d as a Dec.
+func (d Dec) TruncateDec() Dec {
+	return NewDecFromInt(d.TruncateInt())
+}
+
+// String returns the value with exactly Precision decimal places,
+// e.g. "-1.500000000000000000".
+func (d Dec) String() string {
+	abs := new(big.Int).Abs(d.get())
+	str := abs.String()
+	if len(str) <= Precision {
+		str = strings.Repeat("0", Precision-len(str)+1) + str
+	}
+	split := len(str) - Precision
+	str = str[:split] + "." + str[split:]
+	if d.Sign() < 0 {
+		str = "-" + str
+	}
+	return str
+}
+
+// chopPrecisionAndRound divides d by 10^Precision, rounding half to
+// even.  It modifies and returns d.
+func chopPrecisionAndRound(d *big.Int) *big.Int {
+	return quoAndRound(d, precisionMultiplier)
+}
+
+// quoAndRound divides x by y, rounding half to even.  The remainder of
+// a single division decides the rounding, so it is exact.  It modifies
+// and returns x.
+func quoAndRound(x, y *big.Int) *big.Int {
+	neg := x.Sign()*y.Sign() < 0
+	quo, rem := x.QuoRem(x, y, new(big.Int))
+
+	// Round the absolute value so that rounding is symmetric.
+	quo.Abs(quo)
+	rem.Abs(rem)
+	switch rem.Lsh(rem, 1).CmpAbs(y) {
+	case 1:
+		quo.Add(quo, big.NewInt(1))
+	case 0:
+		// Exactly half: round to even.
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	if neg {
+		quo.Neg(quo)
+	}
+	return quo
+}
+
+//----------------------------------------
+// Encoding
+
+// Decs are encoded as their String(), in both JSON and binary.
+
+// MarshalJSON implements json.Marshaler.
+func (d Dec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Dec) UnmarshalJSON(bz []byte) error {
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return err
+	}
+	res, err := NewDecFromStr(s)
+	if err != nil {
+		return err
+	}
+	*d = res
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (d Dec) MarshalBinary() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (d *Dec) UnmarshalBinary(bz []byte) error {
+	res, err := NewDecFromStr(string(bz))
+	if err != nil {
+		return err
+	}
+	*d = res
+	return nil
+}
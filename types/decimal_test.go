@@ -0,0 +1,126 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustDec(t *testing.T, str string) Dec {
+	d, err := NewDecFromStr(str)
+	assert.Nil(t, err, "%s", str)
+	return d
+}
+
+func TestNewDecFromStr(t *testing.T) {
+	cases := []struct {
+		str     string
+		want    string
+		wantErr bool
+	}{
+		{"0", "0.000000000000000000", false},
+		{"1", "1.000000000000000000", false},
+		{"-1.5", "-1.500000000000000000", false},
+		{"0.000000000000000001", "0.000000000000000001", false},
+		{"123456789012345678901234567890.25", "123456789012345678901234567890.250000000000000000", false},
+		{"", "", true},
+		{"1.", "", true},
+		{".5", "", true},
+		{"1.2.3", "", true},
+		{"1e5", "", true},
+		{"+1", "", true},
+		{"0.0000000000000000001", "", true}, // too precise
+	}
+	for _, tc := range cases {
+		d, err := NewDecFromStr(tc.str)
+		if tc.wantErr {
+			assert.NotNil(t, err, "%s", tc.str)
+			continue
+		}
+		assert.Nil(t, err, "%s", tc.str)
+		assert.Equal(t, tc.want, d.String(), "%s", tc.str)
+	}
+	assert.True(t, NewDecWithPrec(15, 1).Equal(mustDec(t, "1.5")))
+	assert.True(t, NewDecFromInt(NewInt(-3)).Equal(NewDec(-3)))
+}
+
+func TestDecArithmetic(t *testing.T) {
+	a, b := mustDec(t, "1.5"), mustDec(t, "0.25")
+	assert.Equal(t, "1.750000000000000000", a.Add(b).String())
+	assert.Equal(t, "1.250000000000000000", a.Sub(b).String())
+	assert.Equal(t, "0.375000000000000000", a.Mul(b).String())
+	assert.Equal(t, "6.000000000000000000", a.Quo(b).String())
+	assert.Equal(t, "4.500000000000000000", a.MulInt(NewInt(3)).String())
+	assert.Equal(t, "0.500000000000000000", a.QuoInt(NewInt(3)).String())
+	assert.Equal(t, "0.333333333333333333", OneDec().Quo(NewDec(3)).String())
+	assert.Equal(t, "0.666666666666666667", NewDec(2).Quo(NewDec(3)).String())
+	assert.Equal(t, "-0.666666666666666667", NewDec(-2).Quo(NewDec(3)).String())
+	assert.True(t, a.GT(b) && a.GTE(b) && b.LT(a) && b.LTE(a))
+	assert.True(t, Dec{}.IsZero())
+	assert.Panics(t, func() { a.Quo(ZeroDec()) })
+
+	// Operands are not modified.
+	assert.Equal(t, "1.500000000000000000", a.String())
+	assert.Equal(t, "0.250000000000000000", b.String())
+}
+
+func TestDecBankersRounding(t *testing.T) {
+	cases := []struct {
+		str   string
+		round int64
+		trunc int64
+	}{
+		{"0.5", 0, 0},
+		{"1.5", 2, 1},
+		{"2.5", 2, 2},
+		{"2.500000000000000001", 3, 2},
+		{"2.499999999999999999", 2, 2},
+		{"-0.5", 0, 0},
+		{"-1.5", -2, -1},
+		{"-2.5", -2, -2},
+	}
+	for _, tc := range cases {
+		d := mustDec(t, tc.str)
+		assert.Equal(t, tc.round, d.RoundInt().Int64(), "%s", tc.str)
+		assert.Equal(t, tc.trunc, d.TruncateInt().Int64(), "%s", tc.str)
+	}
+
+	// Products are rounded at the last decimal place.
+	half := mustDec(t, "0.000000000000000001").Mul(mustDec(t, "0.5"))
+	assert.True(t, half.IsZero())
+	threeHalves := mustDec(t, "0.000000000000000003").Mul(mustDec(t, "0.5"))
+	assert.Equal(t, "0.000000000000000002", threeHalves.String())
+
+	// Quotients are rounded from their exact remainder: this one is
+	// 0.4999999999999999985000000000000000007..., just over a half at
+	// the last place.
+	quo := mustDec(t, "1.999999999999999995").Quo(mustDec(t, "4.000000000000000002"))
+	assert.Equal(t, "0.499999999999999999", quo.String())
+	quo = mustDec(t, "-1.999999999999999995").Quo(mustDec(t, "4.000000000000000002"))
+	assert.Equal(t, "-0.499999999999999999", quo.String())
+	quo = mustDec(t, "0.000000000000000005").Quo(NewDec(10))
+	assert.True(t, quo.IsZero())
+	quo = mustDec(t, "0.000000000000000015").QuoInt(NewInt(10))
+	assert.Equal(t, "0.000000000000000002", quo.String())
+	quo = mustDec(t, "-0.000000000000000015").QuoInt(NewInt(-10))
+	assert.Equal(t, "0.000000000000000002", quo.String())
+}
+
+func TestDecEncoding(t *testing.T) {
+	d := mustDec(t, "-12.345")
+	bz, err := json.Marshal(d)
+	assert.Nil(t, err)
+	assert.Equal(t, `"-12.345000000000000000"`, string(bz))
+	var d2 Dec
+	assert.Nil(t, json.Unmarshal(bz, &d2))
+	assert.True(t, d.Equal(d2))
+
+	bz, err = d.MarshalBinary()
+	assert.Nil(t, err)
+	var d3 Dec
+	assert.Nil(t, d3.UnmarshalBinary(bz))
+	assert.True(t, d.Equal(d3))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`1.5`), &d2))
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DenomMetadata describes a denomination and the unit it is displayed
+// in, e.g. 1 atom (the display denom) = 10^6 uatom (the base denom).
+// Coins are always stored in the base denom.
+type DenomMetadata struct {
+	Base        string `json:"base"`
+	Display     string `json:"display"`
+	Exponent    uint   `json:"exponent"`
+	Description string `json:"description"`
+}
+
+// DenomLookup returns the metadata of a base or display denom.
+type DenomLookup func(denom string) (DenomMetadata, bool)
+
+var reDenom = regexp.MustCompile(fmt.Sprintf(`^%s$`, reDnm))
+
+// ValidateBasic checks that the denoms are valid and the exponent
+// can be represented by a Dec.
+func (meta DenomMetadata) ValidateBasic() error {
+	if !reDenom.MatchString(meta.Base) {
+		return fmt.Errorf("invalid base denom %q", meta.Base)
+	}
+	if !reDenom.MatchString(meta.Display) {
+		return fmt.Errorf("invalid display denom %q", meta.Display)
+	}
+	if meta.Exponent > Precision {
+		return fmt.Errorf("exponent %d of %s exceeds %d", meta.Exponent, meta.Display, Precision)
+	}
+	if meta.Base == meta.Display && meta.Exponent != 0 {
+		return fmt.Errorf("denom %s has a non-zero exponent to itself", meta.Base)
+	}
+	return nil
+}
+
+// ToBaseCoin converts an amount of the display denom to a Coin of the
+// base denom.  It errors if the amount is not a whole number of base
+// units.
+func (meta DenomMetadata) ToBaseCoin(amount Dec) (Coin, error) {
+	base := amount.MulInt(meta.multiplier())
+	if !base.Equal(base.TruncateDec()) {
+		return Coin{}, fmt.Errorf("%v%s is not a whole number of %s", amount, meta.Display, meta.Base)
+	}
+	return Coin{meta.Base, base.TruncateInt()}, nil
+}
+
+// ToDisplay converts an amount of the base denom to the display denom.
+func (meta DenomMetadata) ToDisplay(amount Int) Dec {
+	return NewDecFromInt(amount).QuoInt(meta.multiplier())
+}
+
+// multiplier returns 10^Exponent.
+func (meta DenomMetadata) multiplier() Int {
+	return NewIntFromBigInt(precisionMultiplierOf(int64(meta.Exponent)))
+}
+
+// NewDenomLookup returns a DenomLookup over metas.
+func NewDenomLookup(metas []DenomMetadata) DenomLookup {
+	return func(denom string) (DenomMetadata, bool) {
+		for _, meta := range metas {
+			if meta.Base == denom || meta.Display == denom {
+				return meta, true
+			}
+		}
+		return DenomMetadata{}, false
+	}
+}
+
+//----------------------------------------
+// Parsing
+
+var (
+	reDecAmt  = `[[:digit:]]+(?:\.[[:digit:]]+)?`
+	reDecCoin = regexp.MustCompile(fmt.Sprintf(`^(%s)%s(%s)$`, reDecAmt, reSpc, reDnm))
+)
+
+// ParseDisplayCoin parses a human amount like "1.5atom".  If the denom
+// is a display denom known to lookup, the amount is converted to its
+// base denom, e.g. "1.5atom" becomes 1500000uatom.  Any other denom
+// must have a whole amount, as for ParseCoin.
+func ParseDisplayCoin(coinStr string, lookup DenomLookup) (coin Coin, err error) {
+	coinStr = strings.TrimSpace(coinStr)
+
+	matches := reDecCoin.FindStringSubmatch(coinStr)
+	if matches == nil {
+		err = fmt.Errorf("Invalid coin expression: %s", coinStr)
+		return
+	}
+	denomStr, amountStr := matches[2], matches[1]
+
+	meta, ok := lookup(denomStr)
+	if !ok || meta.Display != denomStr {
+		// Not a display denom, so there is nothing to convert.
+		return ParseCoin(coinStr)
+	}
+	amount, err := NewDecFromStr(amountStr)
+	if err != nil {
+		return
+	}
+	return meta.ToBaseCoin(amount)
+}
+
+// ParseDisplayCoins parses a list of human amounts separated by
+// commas, like ParseCoins, converting display denoms to base denoms
+// with ParseDisplayCoin.  Returned coins are sorted.
+func ParseDisplayCoins(coinsStr string, lookup DenomLookup) (coins Coins, err error) {
+	coinsStr = strings.TrimSpace(coinsStr)
+	if len(coinsStr) == 0 {
+		return nil, nil
+	}
+
+	coinStrs := strings.Split(coinsStr, ",")
+	for _, coinStr := range coinStrs {
+		coin, err := ParseDisplayCoin(coinStr, lookup)
+		if err != nil {
+			return nil, err
+		}
+		coins = append(coins, coin)
+	}
+
+	// Sort coins for determinism.
+	coins.Sort()
+
+	// Validate coins before returning.
+	if !coins.IsValid() {
+		return nil, fmt.Errorf("ParseDisplayCoins invalid: %#v", coins)
+	}
+
+	return coins, nil
+}
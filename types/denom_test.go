@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testDenoms = []DenomMetadata{
+	{Base: "uatom", Display: "atom", Exponent: 6},
+	{Base: "wei", Display: "eth", Exponent: 18},
+	{Base: "photon", Display: "photon", Exponent: 0},
+}
+
+func TestDenomMetadataValidateBasic(t *testing.T) {
+	for _, meta := range testDenoms {
+		assert.Nil(t, meta.ValidateBasic(), "%v", meta)
+	}
+	invalid := []DenomMetadata{
+		{Base: "", Display: "atom", Exponent: 6},
+		{Base: "uatom", Display: "1atom", Exponent: 6},
+		{Base: "wei", Display: "eth", Exponent: 19},
+		{Base: "atom", Display: "atom", Exponent: 6},
+	}
+	for _, meta := range invalid {
+		assert.NotNil(t, meta.ValidateBasic(), "%v", meta)
+	}
+}
+
+func TestDenomMetadataConversion(t *testing.T) {
+	atom := testDenoms[0]
+	coin, err := atom.ToBaseCoin(mustDec(t, "1.5"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1500000uatom", coin.String())
+	assert.Equal(t, "1.500000000000000000", atom.ToDisplay(coin.Amount).String())
+
+	_, err = atom.ToBaseCoin(mustDec(t, "0.0000001"))
+	assert.NotNil(t, err, "Expected fractional base units to be rejected")
+}
+
+func TestParseDisplayCoins(t *testing.T) {
+	lookup := NewDenomLookup(testDenoms)
+	cases := []struct {
+		input    string
+		valid    bool
+		expected string
+	}{
+		{"", true, ""},
+		{"1.5atom", true, "1500000uatom"},
+		{"1atom", true, "1000000uatom"},
+		{"250uatom", true, "250uatom"},
+		{"0.000001atom", true, "1uatom"},
+		{"2.5eth, 3photon", true, "3photon,2500000000000000000wei"},
+		{"10foo", true, "10foo"},
+		{"1.5uatom", false, ""},           // base units are whole
+		{"0.0000001atom", false, ""},      // less than 1uatom
+		{"1.5foo", false, ""},             // unknown denoms are whole
+		{"1atom,1000000uatom", false, ""}, // duplicate denom
+		{"1.atom", false, ""},
+	}
+	for _, tc := range cases {
+		res, err := ParseDisplayCoins(tc.input, lookup)
+		if !tc.valid {
+			assert.NotNil(t, err, "%s: %v", tc.input, res)
+		} else if assert.Nil(t, err, "%s: %v", tc.input, err) {
+			assert.Equal(t, tc.expected, res.String(), "%s", tc.input)
+		}
+	}
+}
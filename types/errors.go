@@ -1,12 +1,43 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/tendermint/go-crypto"
 	"runtime"
+	"strings"
+
+	abci "github.com/tendermint/abci/types"
 )
 
-type CodeType uint32
+// CodeType is the code of an error within its Codespace.
+type CodeType uint16
+
+// CodespaceType identifies the module that defines a CodeType, so that
+// the codes of different modules can't collide.
+type CodespaceType uint16
+
+// ABCICodeType is the code of an ABCI response.  It combines the
+// codespace, in the upper 16 bits, and the code, in the lower 16 bits.
+type ABCICodeType uint32
+
+// ToABCICode combines codespace and code into an ABCICodeType.
+// CodeOK is always 0, whatever the codespace.
+func ToABCICode(codespace CodespaceType, code CodeType) ABCICodeType {
+	if code == CodeOK {
+		return ABCICodeOK
+	}
+	return ABCICodeType(uint32(codespace)<<16 | uint32(code))
+}
+
+// Codespace returns the codespace part of code.
+func (code ABCICodeType) Codespace() CodespaceType {
+	return CodespaceType(code >> 16)
+}
+
+// Code returns the code part of code.
+func (code ABCICodeType) Code() CodeType {
+	return CodeType(code & 0xFFFF)
+}
 
 func (code CodeType) IsOK() bool {
 	if code == CodeOK {
@@ -17,8 +48,15 @@ func (code CodeType) IsOK() bool {
 }
 
 const (
-	// ABCI Response Codes
-	// Base SDK reserves 0 ~ 99.
+	// ABCICodeOK is the ABCI code of a successful response.
+	ABCICodeOK ABCICodeType = 0
+
+	// CodespaceRoot is the codespace of the errors below, which are
+	// common to all modules.  Modules register their own codespaces
+	// with BaseApp.RegisterCodespace.
+	CodespaceRoot CodespaceType = 1
+
+	// Root Codespace Codes
 	CodeOK                  CodeType = 0
 	CodeInternal            CodeType = 1
 	CodeTxParse             CodeType = 2
@@ -28,6 +66,7 @@ const (
 	CodeUnknownRequest      CodeType = 6
 	CodeUnrecognizedAddress CodeType = 7
 	CodeInvalidSequence     CodeType = 8
+	CodeOutOfGas            CodeType = 9
 )
 
 // NOTE: Don't stringer this, we'll put better messages in later.
@@ -49,6 +88,8 @@ func CodeToDefaultMsg(code CodeType) string {
 		return "Unrecognized address"
 	case CodeInvalidSequence:
 		return "Invalid sequence"
+	case CodeOutOfGas:
+		return "Out of gas"
 	default:
 		return fmt.Sprintf("Unknown code %d", code)
 	}
@@ -82,7 +123,7 @@ func ErrUnknownRequest(msg string) Error {
 	return newError(CodeUnknownRequest, msg)
 }
 
-func ErrUnrecognizedAddress(addr crypto.Address) Error {
+func ErrUnrecognizedAddress(addr Address) Error {
 	return newError(CodeUnrecognizedAddress, addr.String())
 }
 
@@ -90,21 +131,111 @@ func ErrInvalidSequence(msg string) Error {
 	return newError(CodeInvalidSequence, msg)
 }
 
+func ErrOutOfGas(msg string) Error {
+	return newError(CodeOutOfGas, msg)
+}
+
+//----------------------------------------
+// ErrorLog
+
+// errorDebug enables traces in ABCI logs.
+var errorDebug = false
+
+// SetErrorDebug enables or disables the traces (with file paths) in
+// ABCI logs.  It is off by default, so that nodes built on different
+// machines produce identical logs.  Only enable it for debugging.
+func SetErrorDebug(debug bool) {
+	errorDebug = debug
+}
+
+// ErrorLog is the machine-readable ABCI log of an Error.
+type ErrorLog struct {
+	Codespace CodespaceType `json:"codespace"`
+	Code      CodeType      `json:"code"`
+	Message   string        `json:"message"`
+	Causes    []string      `json:"causes,omitempty"` // outermost first
+	Trace     []string      `json:"trace,omitempty"`  // only with SetErrorDebug
+	MsgIndex  *int          `json:"msg_index,omitempty"`
+}
+
+// String returns errLog as a single line of JSON.
+func (errLog ErrorLog) String() string {
+	bz, err := json.Marshal(errLog)
+	if err != nil {
+		panic(err)
+	}
+	return string(bz)
+}
+
+// ParseErrorLog parses the ErrorLog from the ABCI log of a failed
+// response.  The ErrorLog is the first line; any further lines are
+// log output of the tx.
+func ParseErrorLog(log string) (errLog ErrorLog, err error) {
+	if i := strings.IndexByte(log, '\n'); i >= 0 {
+		log = log[:i]
+	}
+	err = json.Unmarshal([]byte(log), &errLog)
+	return errLog, err
+}
+
+// WithMsgIndex records in log that it is the log of the failed Msg at
+// index i of a tx.  Logs that are not an ErrorLog are prefixed instead.
+func WithMsgIndex(log string, i int) string {
+	if !strings.ContainsRune(log, '\n') {
+		if errLog, err := ParseErrorLog(log); err == nil {
+			errLog.MsgIndex = &i
+			return errLog.String()
+		}
+	}
+	return fmt.Sprintf("msg %d failed: %s", i, log)
+}
+
+// causeChain returns the messages of cause and of the causes it wraps.
+// Wrappers that don't change the message (e.g. that only add a stack)
+// are skipped.
+func causeChain(cause error) (causes []string) {
+	for cause != nil {
+		msg := cause.Error()
+		if len(causes) == 0 || causes[len(causes)-1] != msg {
+			causes = append(causes, msg)
+		}
+		causer, ok := cause.(interface {
+			Cause() error
+		})
+		if !ok || causer.Cause() == cause {
+			break
+		}
+		cause = causer.Cause()
+	}
+	return causes
+}
+
 //----------------------------------------
 // Error & sdkError
 
 type Error interface {
 	Error() string
-	ABCICode() CodeType
+	Codespace() CodespaceType
+	Code() CodeType
+	ABCICode() ABCICodeType
 	ABCILog() string
 	Trace(msg string) Error
 	TraceCause(cause error, msg string) Error
 	Cause() error
 	Result() Result
+	QueryResult() abci.ResponseQuery
 }
 
-func NewError(code CodeType, msg string) Error {
-	return newError(code, msg)
+// NewError returns an Error of a module's codespace.
+// The default messages of CodespaceRoot are not used for other
+// codespaces.
+func NewError(codespace CodespaceType, code CodeType, msg string) Error {
+	if msg == "" && codespace != CodespaceRoot {
+		msg = fmt.Sprintf("Unknown code %d", code)
+	}
+	err := newError(code, msg)
+	err.codespace = codespace
+	return err
 }
 
 type traceItem struct {
@@ -118,10 +249,11 @@ func (ti traceItem) String() string {
 }
 
 type sdkError struct {
-	code   CodeType
-	msg    string
-	cause  error
-	traces []traceItem
+	codespace CodespaceType
+	code      CodeType
+	msg       string
+	cause     error
+	traces    []traceItem
 }
 
 func newError(code CodeType, msg string) *sdkError {
@@ -130,33 +262,49 @@ func newError(code CodeType, msg string) *sdkError {
 		msg = CodeToDefaultMsg(code)
 	}
 	return &sdkError{
-		code:   code,
-		msg:    msg,
-		cause:  nil,
-		traces: nil,
+		codespace: CodespaceRoot,
+		code:      code,
+		msg:       msg,
+		cause:     nil,
+		traces:    nil,
 	}
 }
 
 // Implements ABCIError.
 func (err *sdkError) Error() string {
-	return fmt.Sprintf("Error{%d:%s,%v,%v}", err.code, err.msg, err.cause, len(err.traces))
+	return fmt.Sprintf("Error{%d:%d:%s,%v,%v}", err.codespace, err.code, err.msg, err.cause, len(err.traces))
 }
 
-// Implements ABCIError.
-func (err *sdkError) ABCICode() CodeType {
+// Implements Error.
+func (err *sdkError) Codespace() CodespaceType {
+	return err.codespace
+}
+
+// Implements Error.
+func (err *sdkError) Code() CodeType {
 	return err.code
 }
 
 // Implements ABCIError.
+func (err *sdkError) ABCICode() ABCICodeType {
+	return ToABCICode(err.codespace, err.code)
+}
+
+// Implements ABCIError.
+// Returns the ErrorLog of err as a single line of JSON.
 func (err *sdkError) ABCILog() string {
-	traceLog := ""
-	for _, ti := range err.traces {
-		traceLog += ti.String() + "\n"
+	errLog := ErrorLog{
+		Codespace: err.codespace,
+		Code:      err.code,
+		Message:   err.msg,
+		Causes:    causeChain(err.cause),
+	}
+	if errorDebug {
+		for _, ti := range err.traces {
+			errLog.Trace = append(errLog.Trace, ti.String())
+		}
 	}
-	return fmt.Sprintf("msg: %v\ntrace:\n%v",
-		err.msg,
-		traceLog,
-	)
+	return errLog.String()
 }
 
 // Add tracing information with msg.
@@ -196,7 +344,15 @@ func (err *sdkError) Cause() error {
 
 func (err *sdkError) Result() Result {
 	return Result{
-		Code: err.ABCICode(),
+		Codespace: err.codespace,
+		Code:      err.code,
+		Log:       err.ABCILog(),
+	}
+}
+
+func (err *sdkError) QueryResult() abci.ResponseQuery {
+	return abci.ResponseQuery{
+		Code: uint32(err.ABCICode()),
 		Log:  err.ABCILog(),
 	}
 }
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestABCILog(t *testing.T) {
+	err := ErrUnauthorized("bad sig").Trace("checking sigs")
+	assert.Equal(t, `{"codespace":1,"code":4,"message":"bad sig"}`, err.ABCILog())
+
+	// Causes are included, outermost first.
+	cause := errors.Wrap(errors.New("inner"), "outer")
+	err = ErrInternal("").TraceCause(cause, "")
+	assert.Equal(t, `{"codespace":1,"code":1,"message":"Internal error","causes":["outer: inner","inner"]}`, err.ABCILog())
+
+	// Module errors have their own codespace.
+	err = NewError(5, 101, "")
+	assert.Equal(t, `{"codespace":5,"code":101,"message":"Unknown code 101"}`, err.ABCILog())
+	assert.Equal(t, err.ABCILog(), err.Result().Log)
+	assert.Equal(t, err.ABCILog(), err.QueryResult().Log)
+}
+
+func TestABCILogDebug(t *testing.T) {
+	SetErrorDebug(true)
+	defer SetErrorDebug(false)
+
+	err := ErrUnauthorized("bad sig").Trace("checking sigs")
+	errLog, parseErr := ParseErrorLog(err.ABCILog())
+	assert.Nil(t, parseErr)
+	if assert.Len(t, errLog.Trace, 1) {
+		assert.Contains(t, errLog.Trace[0], "errors_test.go")
+		assert.True(t, strings.HasSuffix(errLog.Trace[0], "checking sigs"))
+	}
+}
+
+func TestParseErrorLog(t *testing.T) {
+	// Tx log output may follow the ErrorLog.
+	log := ErrTxParse("bad tx").ABCILog() + "\nI[...] some log output\n"
+	errLog, err := ParseErrorLog(log)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrorLog{Codespace: CodespaceRoot, Code: CodeTxParse, Message: "bad tx"}, errLog)
+
+	_, err = ParseErrorLog("not json")
+	assert.NotNil(t, err)
+}
+
+func TestWithMsgIndex(t *testing.T) {
+	log := WithMsgIndex(ErrTxParse("bad tx").ABCILog(), 2)
+	assert.Equal(t, `{"codespace":1,"code":2,"message":"bad tx","msg_index":2}`, log)
+
+	// Free-form logs are prefixed.
+	assert.Equal(t, "msg 2 failed: oops", WithMsgIndex("oops", 2))
+}
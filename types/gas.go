@@ -0,0 +1,86 @@
+package types
+
+import "math"
+
+// Gas measures the amount of work done by a transaction.
+type Gas = int64
+
+// ErrorOutOfGas is the value passed to panic() when a GasMeter runs
+// out of gas.  BaseApp recovers it and returns ErrOutOfGas.
+type ErrorOutOfGas struct {
+	Descriptor string
+}
+
+// GasMeter tracks gas consumption and enforces a limit.
+type GasMeter interface {
+	GasConsumed() Gas
+	Limit() Gas
+
+	// ConsumeGas adds amount to the consumed gas, and panics with
+	// ErrorOutOfGas if the limit is exceeded.
+	ConsumeGas(amount Gas, descriptor string)
+}
+
+type basicGasMeter struct {
+	limit    Gas
+	consumed Gas
+}
+
+// NewGasMeter returns a GasMeter that panics once more than limit
+// gas has been consumed.
+func NewGasMeter(limit Gas) GasMeter {
+	return &basicGasMeter{
+		limit:    limit,
+		consumed: 0,
+	}
+}
+
+func (g *basicGasMeter) GasConsumed() Gas {
+	return g.consumed
+}
+
+func (g *basicGasMeter) Limit() Gas {
+	return g.limit
+}
+
+func (g *basicGasMeter) ConsumeGas(amount Gas, descriptor string) {
+	if amount < 0 {
+		panic("negative gas amount")
+	}
+	if amount > g.limit-g.consumed {
+		g.consumed = g.limit
+		panic(ErrorOutOfGas{descriptor})
+	}
+	g.consumed += amount
+}
+
+type infiniteGasMeter struct {
+	consumed Gas
+}
+
+// NewInfiniteGasMeter returns a GasMeter that counts consumption
+// but never runs out of gas.
+func NewInfiniteGasMeter() GasMeter {
+	return &infiniteGasMeter{
+		consumed: 0,
+	}
+}
+
+func (g *infiniteGasMeter) GasConsumed() Gas {
+	return g.consumed
+}
+
+func (g *infiniteGasMeter) Limit() Gas {
+	return math.MaxInt64
+}
+
+func (g *infiniteGasMeter) ConsumeGas(amount Gas, descriptor string) {
+	if amount < 0 {
+		panic("negative gas amount")
+	}
+	if amount > math.MaxInt64-g.consumed {
+		g.consumed = math.MaxInt64
+		return
+	}
+	g.consumed += amount
+}
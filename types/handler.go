@@ -4,3 +4,21 @@ type Handler func(ctx Context, msg Msg) Result
 
 // If newCtx.IsZero(), ctx is used instead.
 type AnteHandler func(ctx Context, tx Tx) (newCtx Context, result Result, abort bool)
+
+// ChainAnteHandlers returns an AnteHandler that runs ahs in order,
+// passing each the Context returned by the one before.  It stops at
+// the first AnteHandler that aborts.
+func ChainAnteHandlers(ahs ...AnteHandler) AnteHandler {
+	return func(ctx Context, tx Tx) (newCtx Context, result Result, abort bool) {
+		for _, ah := range ahs {
+			newCtx, result, abort = ah(ctx, tx)
+			if abort {
+				return newCtx, result, true
+			}
+			if !newCtx.IsZero() {
+				ctx = newCtx
+			}
+		}
+		return ctx, result, false
+	}
+}
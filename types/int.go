@@ -0,0 +1,204 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// maxIntBits is the maximum bit length of an Int.  Arithmetic that
+// would exceed it panics, rather than silently overflowing.
+const maxIntBits = 255
+
+// Int is an arbitrary-precision signed integer, used for coin amounts.
+// Its zero value is 0.  Ints are immutable: every operation returns a
+// new Int.
+type Int struct {
+	i *big.Int
+}
+
+// NewInt returns an Int with value n.
+func NewInt(n int64) Int {
+	return Int{big.NewInt(n)}
+}
+
+// NewIntFromBigInt returns an Int with the value of i.
+// It panics if i is out of range.
+func NewIntFromBigInt(i *big.Int) Int {
+	return checkInt(new(big.Int).Set(i))
+}
+
+// NewIntFromString parses a base 10 integer, e.g. "-1234".
+// ok is false if s is not a valid integer or is out of range.
+func NewIntFromString(s string) (res Int, ok bool) {
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok || i.BitLen() > maxIntBits {
+		return Int{}, false
+	}
+	return Int{i}, true
+}
+
+// ZeroInt returns an Int with value 0.
+func ZeroInt() Int { return NewInt(0) }
+
+// OneInt returns an Int with value 1.
+func OneInt() Int { return NewInt(1) }
+
+// checkInt panics if i is out of range.
+func checkInt(i *big.Int) Int {
+	if i.BitLen() > maxIntBits {
+		panic("Int overflow")
+	}
+	return Int{i}
+}
+
+// get returns the underlying big.Int, treating nil as 0.
+// The result must not be modified.
+func (i Int) get() *big.Int {
+	if i.i == nil {
+		return new(big.Int)
+	}
+	return i.i
+}
+
+// BigInt returns a copy of the value as a big.Int.
+func (i Int) BigInt() *big.Int {
+	return new(big.Int).Set(i.get())
+}
+
+// IsInt64 returns true if the value fits in an int64.
+func (i Int) IsInt64() bool {
+	return i.get().IsInt64()
+}
+
+// Int64 returns the value as an int64.
+// It panics if the value does not fit.
+func (i Int) Int64() int64 {
+	if !i.IsInt64() {
+		panic("Int64() out of bounds")
+	}
+	return i.get().Int64()
+}
+
+// IsZero returns true if the value is 0.
+func (i Int) IsZero() bool {
+	return i.get().Sign() == 0
+}
+
+// Sign returns -1, 0 or +1 according to the sign of the value.
+func (i Int) Sign() int {
+	return i.get().Sign()
+}
+
+// Equal returns true if i == i2.
+func (i Int) Equal(i2 Int) bool {
+	return i.get().Cmp(i2.get()) == 0
+}
+
+// GT returns true if i > i2.
+func (i Int) GT(i2 Int) bool {
+	return i.get().Cmp(i2.get()) > 0
+}
+
+// LT returns true if i < i2.
+func (i Int) LT(i2 Int) bool {
+	return i.get().Cmp(i2.get()) < 0
+}
+
+// Add returns i + i2.  It panics on overflow.
+func (i Int) Add(i2 Int) Int {
+	return checkInt(new(big.Int).Add(i.get(), i2.get()))
+}
+
+// AddRaw returns i + i2.  It panics on overflow.
+func (i Int) AddRaw(i2 int64) Int {
+	return i.Add(NewInt(i2))
+}
+
+// Sub returns i - i2.  It panics on overflow.
+func (i Int) Sub(i2 Int) Int {
+	return checkInt(new(big.Int).Sub(i.get(), i2.get()))
+}
+
+// SubRaw returns i - i2.  It panics on overflow.
+func (i Int) SubRaw(i2 int64) Int {
+	return i.Sub(NewInt(i2))
+}
+
+// Mul returns i * i2.  It panics on overflow.
+func (i Int) Mul(i2 Int) Int {
+	// Check the operands first so that we never compute huge products.
+	if i.get().BitLen()+i2.get().BitLen()-1 > maxIntBits {
+		panic("Int overflow")
+	}
+	return checkInt(new(big.Int).Mul(i.get(), i2.get()))
+}
+
+// MulRaw returns i * i2.  It panics on overflow.
+func (i Int) MulRaw(i2 int64) Int {
+	return i.Mul(NewInt(i2))
+}
+
+// Div returns i / i2, truncated towards zero.
+// It panics if i2 is 0.
+func (i Int) Div(i2 Int) Int {
+	if i2.IsZero() {
+		panic("division by zero")
+	}
+	return Int{new(big.Int).Quo(i.get(), i2.get())}
+}
+
+// DivRaw returns i / i2, truncated towards zero.
+// It panics if i2 is 0.
+func (i Int) DivRaw(i2 int64) Int {
+	return i.Div(NewInt(i2))
+}
+
+// Neg returns -i.
+func (i Int) Neg() Int {
+	return Int{new(big.Int).Neg(i.get())}
+}
+
+// String returns the value in base 10.
+func (i Int) String() string {
+	return i.get().String()
+}
+
+//----------------------------------------
+// Encoding
+
+// Ints are encoded as base 10 strings, in both JSON and binary, so
+// that large values survive JSON decoders that use float64.
+
+// MarshalJSON implements json.Marshaler.
+func (i Int) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *Int) UnmarshalJSON(bz []byte) error {
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		return err
+	}
+	return i.unmarshalString(s)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (i Int) MarshalBinary() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (i *Int) UnmarshalBinary(bz []byte) error {
+	return i.unmarshalString(string(bz))
+}
+
+func (i *Int) unmarshalString(s string) error {
+	res, ok := NewIntFromString(s)
+	if !ok {
+		return fmt.Errorf("invalid Int %q", s)
+	}
+	*i = res
+	return nil
+}
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntArithmetic(t *testing.T) {
+	a, b := NewInt(7), NewInt(-3)
+	assert.Equal(t, "4", a.Add(b).String())
+	assert.Equal(t, "10", a.Sub(b).String())
+	assert.Equal(t, "-21", a.Mul(b).String())
+	assert.Equal(t, "-2", a.Div(b).String())
+	assert.Equal(t, "3", b.Neg().String())
+	assert.Equal(t, "8", a.AddRaw(1).String())
+	assert.True(t, a.GT(b))
+	assert.True(t, b.LT(a))
+	assert.True(t, a.Equal(NewInt(7)))
+	assert.True(t, Int{}.IsZero())
+	assert.True(t, Int{}.Equal(ZeroInt()))
+	assert.Equal(t, -1, b.Sign())
+	assert.Panics(t, func() { a.Div(ZeroInt()) })
+
+	// Operands are not modified.
+	assert.Equal(t, "7", a.String())
+	assert.Equal(t, "-3", b.String())
+}
+
+func TestIntOverflow(t *testing.T) {
+	max := NewIntFromBigInt(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), maxIntBits), big.NewInt(1)))
+	assert.Panics(t, func() { max.AddRaw(1) })
+	assert.Panics(t, func() { max.Neg().SubRaw(1) })
+	assert.Panics(t, func() { max.MulRaw(2) })
+	assert.NotPanics(t, func() { max.Sub(max) })
+
+	_, ok := NewIntFromString(max.String())
+	assert.True(t, ok)
+	_, ok = NewIntFromString(max.String() + "0")
+	assert.False(t, ok)
+
+	large := NewInt(math.MaxInt64).AddRaw(1)
+	assert.False(t, large.IsInt64())
+	assert.Panics(t, func() { large.Int64() })
+	assert.Equal(t, int64(math.MaxInt64), large.SubRaw(1).Int64())
+}
+
+func TestIntEncoding(t *testing.T) {
+	amount := "1000000000000000000000000" // 1e6 tokens with 18 decimals
+	i, ok := NewIntFromString(amount)
+	assert.True(t, ok)
+
+	bz, err := json.Marshal(i)
+	assert.Nil(t, err)
+	assert.Equal(t, `"`+amount+`"`, string(bz))
+	var i2 Int
+	assert.Nil(t, json.Unmarshal(bz, &i2))
+	assert.True(t, i.Equal(i2))
+
+	bz, err = i.MarshalBinary()
+	assert.Nil(t, err)
+	var i3 Int
+	assert.Nil(t, i3.UnmarshalBinary(bz))
+	assert.True(t, i.Equal(i3))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`1`), &i2))
+	assert.NotNil(t, json.Unmarshal([]byte(`"1.5"`), &i2))
+	assert.NotNil(t, json.Unmarshal([]byte(`"`+strings.Repeat("9", 100)+`"`), &i2))
+}
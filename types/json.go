@@ -0,0 +1,35 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SortJSON re-encodes toSortJSON with its object keys sorted, without
+// whitespace and without HTML escaping.  Numbers are kept as written.
+func SortJSON(toSortJSON []byte) ([]byte, error) {
+	var c interface{}
+	dec := json.NewDecoder(bytes.NewReader(toSortJSON))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+
+	// json.Encoder sorts map keys.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// MustSortJSON is like SortJSON but panics on error.
+func MustSortJSON(toSortJSON []byte) []byte {
+	js, err := SortJSON(toSortJSON)
+	if err != nil {
+		panic(err)
+	}
+	return js
+}
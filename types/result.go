@@ -8,6 +8,9 @@ import (
 // Result is the union of ResponseDeliverTx and ResponseCheckTx.
 type Result struct {
 
+	// Codespace is the codespace of Code.
+	Codespace CodespaceType
+
 	// Code is the response code, is stored back on the chain.
 	Code CodeType
 
@@ -20,7 +23,7 @@ type Result struct {
 	// GasWanted is the maximum units of work we allow this tx to perform.
 	GasWanted int64
 
-	// GasUsed is the amount of gas actually consumed. NOTE: not used.
+	// GasUsed is the amount of gas actually consumed.
 	GasUsed int64
 
 	// Tx fee amount and denom.
@@ -34,6 +37,12 @@ type Result struct {
 	Tags []cmn.KVPair
 }
 
+// ABCICode returns the code of the ABCI response,
+// which combines Codespace and Code.
+func (res Result) ABCICode() ABCICodeType {
+	return ToABCICode(res.Codespace, res.Code)
+}
+
 // TODO: In the future, more codes may be OK.
 func (res Result) IsOK() bool {
 	return res.Code.IsOK()
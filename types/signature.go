@@ -1,9 +1,56 @@
 package types
 
-import crypto "github.com/tendermint/go-crypto"
+import (
+	"encoding/json"
+
+	crypto "github.com/tendermint/go-crypto"
+)
 
 type StdSignature struct {
 	crypto.PubKey // optional
 	crypto.Signature
-	Sequence int64
+	AccountNumber int64
+	Sequence      int64
+}
+
+// StdSignDoc is the document every signer of a StdTx signs.  It binds
+// the signature to the chain, to the account number and sequence of
+// every signer, and to the fee, so that it cannot be replayed, even if
+// an account is deleted and recreated.
+type StdSignDoc struct {
+	ChainID        string            `json:"chain_id"`
+	AccountNumbers []int64           `json:"account_numbers"`
+	Sequences      []int64           `json:"sequences"`
+	Fee            StdFee            `json:"fee"`
+	Msgs           []json.RawMessage `json:"msgs"`
+}
+
+// StdSignBytes returns the canonical encoding of the StdSignDoc:
+// JSON with sorted keys, no whitespace and no HTML escaping.
+// accountNumbers and sequences are those of the signers, in order.
+func StdSignBytes(chainID string, accountNumbers []int64, sequences []int64, fee StdFee, msgs []Msg) []byte {
+	if accountNumbers == nil {
+		accountNumbers = []int64{}
+	}
+	if sequences == nil {
+		sequences = []int64{}
+	}
+	if fee.Amount == nil {
+		fee.Amount = Coins{}
+	}
+	msgsBytes := make([]json.RawMessage, len(msgs))
+	for i, msg := range msgs {
+		msgsBytes[i] = json.RawMessage(msg.GetSignBytes())
+	}
+	bz, err := json.Marshal(StdSignDoc{
+		ChainID:        chainID,
+		AccountNumbers: accountNumbers,
+		Sequences:      sequences,
+		Fee:            fee,
+		Msgs:           msgsBytes,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return MustSortJSON(bz)
 }
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type signDocMsg struct {
+	signBytes string
+}
+
+func (msg signDocMsg) Type() string                    { return "test" }
+func (msg signDocMsg) Get(key interface{}) interface{} { return nil }
+func (msg signDocMsg) GetSignBytes() []byte            { return []byte(msg.signBytes) }
+func (msg signDocMsg) ValidateBasic() Error            { return nil }
+func (msg signDocMsg) GetSigners() []Address           { return nil }
+
+func TestSortJSON(t *testing.T) {
+	cases := []struct {
+		unsorted string
+		want     string
+		wantErr  bool
+	}{
+		{`{"cosmos":"foo", "atom":"bar",  "tendermint":"foobar"}`,
+			`{"atom":"bar","cosmos":"foo","tendermint":"foobar"}`, false},
+		{`{"consensus_params":{"block_size_params":{"max_bytes":22020096,"max_txs":100000}},"a":[3,1]}`,
+			`{"a":[3,1],"consensus_params":{"block_size_params":{"max_bytes":22020096,"max_txs":100000}}}`, false},
+		{`{"big":123456789012345678901234567890,"html":"<&>"}`,
+			`{"big":123456789012345678901234567890,"html":"<&>"}`, false},
+		{`"string"`, `"string"`, false},
+		{`{"a":}`, "", true},
+	}
+	for i, tc := range cases {
+		got, err := SortJSON([]byte(tc.unsorted))
+		if tc.wantErr {
+			assert.NotNil(t, err, "%d", i)
+			assert.Panics(t, func() { MustSortJSON([]byte(tc.unsorted)) }, "%d", i)
+			continue
+		}
+		assert.Nil(t, err, "%d: %v", i, err)
+		assert.Equal(t, tc.want, string(got), "%d", i)
+	}
+}
+
+func TestStdSignBytes(t *testing.T) {
+	msgs := []Msg{
+		signDocMsg{`{"a":"x","b":1}`},
+		signDocMsg{`{"z":[],"c":{"y":2,"x":true}}`},
+	}
+	cases := []struct {
+		chainID        string
+		accountNumbers []int64
+		sequences      []int64
+		fee            StdFee
+		msgs           []Msg
+		want           string
+	}{
+		{"test-chain", []int64{3, 4}, []int64{1, 2}, NewStdFee(10000, NewCoin("atom", 5)), msgs[:1],
+			`{"account_numbers":[3,4],"chain_id":"test-chain","fee":{"amount":[{"amount":"5","denom":"atom"}],"gas":10000},"msgs":[{"a":"x","b":1}],"sequences":[1,2]}`},
+		{"test-chain", []int64{0}, []int64{0}, NewStdFee(10000, NewCoin("atom", 5)), msgs,
+			`{"account_numbers":[0],"chain_id":"test-chain","fee":{"amount":[{"amount":"5","denom":"atom"}],"gas":10000},"msgs":[{"a":"x","b":1},{"c":{"x":true,"y":2},"z":[]}],"sequences":[0]}`},
+		{"", nil, nil, NewStdFee(0), msgs[:1],
+			`{"account_numbers":[],"chain_id":"","fee":{"amount":[],"gas":0},"msgs":[{"a":"x","b":1}],"sequences":[]}`},
+	}
+	for i, tc := range cases {
+		got := StdSignBytes(tc.chainID, tc.accountNumbers, tc.sequences, tc.fee, tc.msgs)
+		assert.Equal(t, tc.want, string(got), "%d", i)
+	}
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 
+	abci "github.com/tendermint/abci/types"
 	dbm "github.com/tendermint/tmlibs/db"
 )
 
@@ -19,6 +20,12 @@ type Committer interface {
 	LastCommitID() CommitID
 }
 
+// Queryable allows a Store to expose internal state to the abci.Query
+// interface.
+type Queryable interface {
+	Query(abci.RequestQuery) abci.ResponseQuery
+}
+
 // Stores of MultiStore must implement CommitStore.
 type CommitStore interface {
 	Committer
@@ -53,6 +60,8 @@ type CommitMultiStore interface {
 	MultiStore
 
 	// Mount a store of type.
+	// If db is nil, the store gets its own prefix of the multistore's
+	// db, unless the db was first committed without prefixes.
 	MountStoreWithDB(key StoreKey, typ StoreType, db dbm.DB)
 
 	// Panics on a nil key.
@@ -98,6 +107,9 @@ type KVStore interface {
 	// CONTRACT: No writes may happen within a domain while an iterator exists over it.
 	ReverseIterator(start, end []byte) Iterator
 
+	// Gas returns a KVStore that charges the GasMeter for each access.
+	Gas(GasMeter) KVStore
+
 	// TODO Not yet implemented.
 	// CreateSubKVStore(key *storeKey) (KVStore, error)
 
@@ -109,6 +121,22 @@ type KVStore interface {
 // Alias iterator to db's Iterator for convenience.
 type Iterator = dbm.Iterator
 
+// PrefixEndBytes returns the end of the domain of keys with prefix,
+// for use with Iterator(prefix, PrefixEndBytes(prefix)).  It returns
+// nil, i.e. no end, if prefix is empty or only has 0xFF bytes.
+func PrefixEndBytes(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for len(end) > 0 {
+		if end[len(end)-1] != 0xFF {
+			end[len(end)-1]++
+			return end
+		}
+		end = end[:len(end)-1]
+	}
+	return nil
+}
+
 // CacheKVStore cache-wraps a KVStore.  After calling .Write() on
 // the CacheKVStore, all previously created CacheKVStores on the
 // object expire.
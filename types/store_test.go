@@ -0,0 +1,22 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixEndBytes(t *testing.T) {
+	cases := []struct {
+		prefix []byte
+		want   []byte
+	}{
+		{[]byte("account:"), []byte("account;")},
+		{[]byte{0x01, 0xFF}, []byte{0x02}},
+		{[]byte{0xFF, 0xFF}, nil},
+		{[]byte{}, nil},
+	}
+	for i, tc := range cases {
+		assert.Equal(t, tc.want, PrefixEndBytes(tc.prefix), "%d", i)
+	}
+}
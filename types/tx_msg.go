@@ -1,19 +1,19 @@
 package types
 
-import (
-	crypto "github.com/tendermint/go-crypto"
-)
-
 type Msg interface {
 
-	// Return the message type.
-	// Must be alphanumeric or empty.
+	// Return the message type, which is used for routing.
+	// Must be alphanumeric segments separated by "/",
+	// e.g. "bank/send".
 	Type() string
 
 	// Get some property of the Msg.
 	Get(key interface{}) (value interface{})
 
 	// Get the canonical byte representation of the Msg.
+	// CONTRACT: Must be JSON with sorted keys and no whitespace,
+	// e.g. as returned by MustSortJSON, so that it can be embedded
+	// in a StdSignDoc.
 	GetSignBytes() []byte
 
 	// ValidateBasic does a simple validation check that
@@ -23,37 +23,97 @@ type Msg interface {
 	// Signers returns the addrs of signers that must sign.
 	// CONTRACT: All signatures must be present to be valid.
 	// CONTRACT: Returns addrs in some deterministic order.
-	GetSigners() []crypto.Address
+	GetSigners() []Address
 }
 
 type Tx interface {
 
-	// Gets the Msg.
-	GetMsg() Msg
+	// Gets the Msgs, which are run in order.
+	// All Msgs succeed or fail together.
+	GetMsgs() []Msg
+
+	// Signers returns the addrs of signers that must sign the Tx.
+	// CONTRACT: This is the union of the GetSigners() of every Msg,
+	// without duplicates, in order of first appearance.
+	GetSigners() []Address
 
 	// The address that pays the base fee for this message.  The fee is
-	// deducted before the Msg is processed.
-	GetFeePayer() crypto.Address
+	// deducted before the Msgs are processed.
+	GetFeePayer() Address
 
-	// Signatures returns the signature of signers who signed the Msg.
+	// Signatures returns the signature of signers who signed the Msgs.
 	// CONTRACT: Length returned is same as length of
-	// pubkeys returned from MsgKeySigners, and the order
+	// addrs returned from GetSigners, and the order
 	// matches.
 	// CONTRACT: If the signature is missing (ie the Msg is
 	// invalid), then the corresponding signature is
 	// .Empty().
 	GetSignatures() []StdSignature
+
+	// The fee paid by the fee payer, and the maximum amount of gas
+	// the Tx may consume.  Execution aborts with ErrOutOfGas once
+	// Gas is exceeded.
+	GetFee() StdFee
 }
 
 var _ Tx = (*StdTx)(nil)
 
 type StdTx struct {
-	Msg
+	Msgs       []Msg
+	Fee        StdFee
 	Signatures []StdSignature
 }
 
-func (tx StdTx) GetMsg() Msg                   { return tx.Msg }
-func (tx StdTx) GetFeePayer() crypto.Address   { return tx.Signatures[0].PubKey.Address() }
+func NewStdTx(msgs []Msg, fee StdFee, sigs []StdSignature) StdTx {
+	return StdTx{
+		Msgs:       msgs,
+		Fee:        fee,
+		Signatures: sigs,
+	}
+}
+
+func (tx StdTx) GetMsgs() []Msg                { return tx.Msgs }
+func (tx StdTx) GetSigners() []Address         { return MsgsSigners(tx.Msgs) }
 func (tx StdTx) GetSignatures() []StdSignature { return tx.Signatures }
+func (tx StdTx) GetFee() StdFee                { return tx.Fee }
+
+// The first signer pays the fee.
+func (tx StdTx) GetFeePayer() Address {
+	signers := tx.GetSigners()
+	if len(signers) == 0 {
+		return nil
+	}
+	return signers[0]
+}
 
 type TxDecoder func(txBytes []byte) (Tx, Error)
+
+// StdFee is the fee of a Tx, and the gas it may consume.
+type StdFee struct {
+	Amount Coins `json:"amount"`
+	Gas    int64 `json:"gas"`
+}
+
+func NewStdFee(gas int64, amount ...Coin) StdFee {
+	return StdFee{
+		Amount: amount,
+		Gas:    gas,
+	}
+}
+
+// MsgsSigners returns the union of the signers of msgs,
+// without duplicates, in order of first appearance.
+func MsgsSigners(msgs []Msg) []Address {
+	var signers []Address
+	seen := make(map[string]bool)
+	for _, msg := range msgs {
+		for _, addr := range msg.GetSigners() {
+			if seen[string(addr)] {
+				continue
+			}
+			seen[string(addr)] = true
+			signers = append(signers, addr)
+		}
+	}
+	return signers
+}
@@ -1,11 +1,17 @@
 package auth
 
 import (
+	"bytes"
+	"fmt"
+
 	bam "github.com/cosmos/cosmos-sdk/baseapp"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
-func NewAnteHandler(accountMapper sdk.AccountMapper) sdk.AnteHandler {
+// NewAnteHandler returns an AnteHandler that deducts the fee of a tx
+// from its fee payer into feePool, then checks the signatures and
+// increments the sequences of its signers.
+func NewAnteHandler(accountMapper sdk.AccountMapper, feePool FeePool) sdk.AnteHandler {
 	return func(
 		ctx sdk.Context, tx sdk.Tx,
 	) (_ sdk.Context, _ sdk.Result, abort bool) {
@@ -21,8 +27,30 @@ func NewAnteHandler(accountMapper sdk.AccountMapper) sdk.AnteHandler {
 					sdk.ErrUnrecognizedAddress(payerAddr).Result(),
 					true
 			}
-			// TODO: Charge fee from payerAcc.
-			// TODO: accountMapper.SetAccount(ctx, payerAddr)
+			if _, ok := payerAcc.(*ModuleAccount); ok {
+				return ctx,
+					sdk.ErrUnauthorized("module account can't pay fees").Result(),
+					true
+			}
+			fee := tx.GetFee()
+			if !fee.Amount.IsValid() || !fee.Amount.IsNotNegative() {
+				return ctx,
+					sdk.ErrTxParse(fmt.Sprintf("invalid fee %s", fee.Amount)).Result(),
+					true
+			}
+			if !fee.Amount.IsZero() {
+				// Still vesting coins can't pay fees.
+				spendable := SpendableCoins(payerAcc, ctx.BlockTime())
+				if !spendable.IsGTE(fee.Amount) {
+					errMsg := fmt.Sprintf("%s < %s", spendable, fee.Amount)
+					return ctx,
+						sdk.ErrInsufficientFunds(errMsg).Result(),
+						true
+				}
+				payerAcc.SetCoins(payerAcc.GetCoins().Minus(fee.Amount))
+				accountMapper.SetAccount(ctx, payerAcc)
+				feePool.addCollectedFees(ctx, fee.Amount)
+			}
 		} else {
 			// TODO: Ensure that some other spam prevention is used.
 			// NOTE: bam.TestApp.RunDeliverMsg/RunCheckMsg will
@@ -31,8 +59,11 @@ func NewAnteHandler(accountMapper sdk.AccountMapper) sdk.AnteHandler {
 
 		var sigs = tx.GetSignatures()
 
+		// When simulating, signatures may be left out.
+		var skipSigs = ctx.SkipSigCheck()
+
 		// Assert that there are signatures.
-		if !bam.IsTestAppTx(tx) {
+		if !bam.IsTestAppTx(tx) && !skipSigs {
 			if len(sigs) == 0 {
 				return ctx,
 					sdk.ErrUnauthorized("no signers").Result(),
@@ -40,29 +71,70 @@ func NewAnteHandler(accountMapper sdk.AccountMapper) sdk.AnteHandler {
 			}
 		}
 
-		// Ensure that sigs are correct.
-		var msg = tx.GetMsg()
-		var signerAddrs = msg.GetSigners()
+		var signerAddrs = tx.GetSigners()
 		var signerAccs = make([]sdk.Account, len(signerAddrs))
 
 		// Assert that number of signatures is correct.
 		if !bam.IsTestAppTx(tx) {
-			if len(sigs) != len(signerAddrs) {
+			if len(sigs) != len(signerAddrs) && !skipSigs {
 				return ctx,
 					sdk.ErrUnauthorized("wrong number of signers").Result(),
 					true
 			}
 
+			// Ensure that sigs are correct.
+			// Every signer signs the same StdSignDoc, which binds
+			// the chain, the account numbers, the sequences, the fee
+			// and all of the Msgs.
+			var signBytes []byte
+			if !skipSigs {
+				signBytes = sdk.StdSignBytes(ctx.ChainID(), sigAccountNumbers(sigs), sigSequences(sigs), tx.GetFee(), tx.GetMsgs())
+			}
+
 			// Check each nonce and sig.
 			// TODO Refactor out.
-			for i, sig := range sigs {
+			for i, signerAddr := range signerAddrs {
 
-				var signerAcc = accountMapper.GetAccount(ctx, signerAddrs[i])
+				var signerAcc = accountMapper.GetAccount(ctx, signerAddr)
+				if signerAcc == nil {
+					return ctx,
+						sdk.ErrUnrecognizedAddress(signerAddr).Result(),
+						true
+				}
+
+				// Module accounts are moved by code, never by a tx.
+				if _, ok := signerAcc.(*ModuleAccount); ok {
+					return ctx,
+						sdk.ErrUnauthorized("module account can't sign").Result(),
+						true
+				}
 				signerAccs[i] = signerAcc
 
-				// If no pubkey, set pubkey.
-				if signerAcc.GetPubKey() == nil {
-					err := signerAcc.SetPubKey(sig.PubKey)
+				// Without sigs, only increment the sequence number,
+				// so that the gas used is close to that of a signed tx.
+				if skipSigs {
+					signerAcc.SetSequence(signerAcc.GetSequence() + 1)
+					accountMapper.SetAccount(ctx, signerAcc)
+					continue
+				}
+				sig := sigs[i]
+
+				// If no pubkey, set the pubkey of the sig, which must
+				// match the signer's address.
+				pubKey := signerAcc.GetPubKey()
+				if pubKey == nil {
+					if sig.PubKey == nil {
+						return ctx,
+							sdk.ErrUnauthorized("signer has no pubkey").Result(),
+							true
+					}
+					if !bytes.Equal(sig.PubKey.Address(), signerAddr) {
+						return ctx,
+							sdk.ErrUnauthorized("pubkey does not match signer address").Result(),
+							true
+					}
+					pubKey = sig.PubKey
+					err := signerAcc.SetPubKey(pubKey)
 					if err != nil {
 						return ctx,
 							sdk.ErrInternal("setting PubKey on signer").Result(),
@@ -70,6 +142,14 @@ func NewAnteHandler(accountMapper sdk.AccountMapper) sdk.AnteHandler {
 					}
 				}
 
+				// Check the account number, which changes if the
+				// account was deleted and recreated.
+				if signerAcc.GetAccountNumber() != sig.AccountNumber {
+					return ctx,
+						sdk.ErrInvalidSequence("invalid account number").Result(),
+						true
+				}
+
 				// Check and increment sequence number.
 				seq := signerAcc.GetSequence()
 				if seq != sig.Sequence {
@@ -79,8 +159,9 @@ func NewAnteHandler(accountMapper sdk.AccountMapper) sdk.AnteHandler {
 				}
 				signerAcc.SetSequence(seq + 1)
 
-				// Check sig.
-				if !sig.PubKey.VerifyBytes(msg.GetSignBytes(), sig.Signature) {
+				// Check sig.  A PubKeyMultisigThreshold accepts a
+				// Multisignature with at least k valid member sigs.
+				if sig.Signature == nil || !pubKey.VerifyBytes(signBytes, sig.Signature) {
 					return ctx,
 						sdk.ErrUnauthorized("").Result(),
 						true
@@ -95,3 +176,21 @@ func NewAnteHandler(accountMapper sdk.AccountMapper) sdk.AnteHandler {
 		return ctx, sdk.Result{}, false // continue...
 	}
 }
+
+// sigAccountNumbers returns the account numbers of sigs, in order.
+func sigAccountNumbers(sigs []sdk.StdSignature) []int64 {
+	accNumbers := make([]int64, len(sigs))
+	for i, sig := range sigs {
+		accNumbers[i] = sig.AccountNumber
+	}
+	return accNumbers
+}
+
+// sigSequences returns the sequences of sigs, in order.
+func sigSequences(sigs []sdk.StdSignature) []int64 {
+	seqs := make([]int64, len(sigs))
+	for i, sig := range sigs {
+		seqs[i] = sig.Sequence
+	}
+	return seqs
+}
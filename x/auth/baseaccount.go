@@ -17,13 +17,14 @@ var _ sdk.Account = (*BaseAccount)(nil)
 // Extend this by embedding this in your AppAccount.
 // See the examples/basecoin/types/account.go for an example.
 type BaseAccount struct {
-	Address  crypto.Address `json:"address"`
-	Coins    sdk.Coins      `json:"coins"`
-	PubKey   crypto.PubKey  `json:"public_key"`
-	Sequence int64          `json:"sequence"`
+	Address       sdk.Address   `json:"address"`
+	Coins         sdk.Coins     `json:"coins"`
+	PubKey        crypto.PubKey `json:"public_key"`
+	AccountNumber int64         `json:"account_number"`
+	Sequence      int64         `json:"sequence"`
 }
 
-func NewBaseAccountWithAddress(addr crypto.Address) BaseAccount {
+func NewBaseAccountWithAddress(addr sdk.Address) BaseAccount {
 	return BaseAccount{
 		Address: addr,
 	}
@@ -40,12 +41,12 @@ func (acc *BaseAccount) Set(key interface{}, value interface{}) error {
 }
 
 // Implements sdk.Account.
-func (acc BaseAccount) GetAddress() crypto.Address {
+func (acc BaseAccount) GetAddress() sdk.Address {
 	return acc.Address
 }
 
 // Implements sdk.Account.
-func (acc *BaseAccount) SetAddress(addr crypto.Address) error {
+func (acc *BaseAccount) SetAddress(addr sdk.Address) error {
 	if len(acc.Address) != 0 {
 		return errors.New("cannot override BaseAccount address")
 	}
@@ -53,6 +54,17 @@ func (acc *BaseAccount) SetAddress(addr crypto.Address) error {
 	return nil
 }
 
+// Implements sdk.Account.
+func (acc *BaseAccount) GetAccountNumber() int64 {
+	return acc.AccountNumber
+}
+
+// Implements sdk.Account.
+func (acc *BaseAccount) SetAccountNumber(accNumber int64) error {
+	acc.AccountNumber = accNumber
+	return nil
+}
+
 // Implements sdk.Account.
 func (acc BaseAccount) GetPubKey() crypto.PubKey {
 	return acc.PubKey
@@ -95,4 +107,14 @@ func (acc *BaseAccount) SetSequence(seq int64) error {
 func RegisterWireBaseAccount(cdc *wire.Codec) {
 	// Register crypto.[PubKey,PrivKey,Signature] types.
 	crypto.RegisterWire(cdc)
+
+	// Register the multisig PubKey and Signature types.
+	RegisterWireMultisig(cdc)
+
+	// Register the sdk.Account types of this module.
+	cdc.RegisterInterface((*sdk.Account)(nil), nil)
+	cdc.RegisterConcrete(&BaseAccount{}, "cosmos-sdk/BaseAccount", nil)
+	cdc.RegisterConcrete(&ContinuousVestingAccount{}, "cosmos-sdk/ContinuousVestingAccount", nil)
+	cdc.RegisterConcrete(&DelayedVestingAccount{}, "cosmos-sdk/DelayedVestingAccount", nil)
+	cdc.RegisterConcrete(&ModuleAccount{}, "cosmos-sdk/ModuleAccount", nil)
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"encoding/json"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -12,8 +13,8 @@ import (
 func TestBaseAccount(t *testing.T) {
 	key := crypto.GenPrivKeyEd25519()
 	pub := key.PubKey()
-	addr := pub.Address()
-	someCoins := sdk.Coins{{"atom", 123}, {"eth", 246}}
+	addr := sdk.NewAddress(pub)
+	someCoins := sdk.Coins{sdk.NewCoin("atom", 123), sdk.NewCoin("eth", 246)}
 	seq := int64(7)
 
 	acc := NewBaseAccountWithAddress(addr)
@@ -36,6 +37,10 @@ func TestBaseAccount(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, seq, acc.GetSequence())
 
+	err = acc.SetAccountNumber(5)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(5), acc.GetAccountNumber())
+
 	b, err := codec.MarshalBinary(acc)
 	assert.Nil(t, err)
 
@@ -48,3 +53,18 @@ func TestBaseAccount(t *testing.T) {
 	err = codec.UnmarshalBinary(b[:len(b)/2], &acc2)
 	assert.NotNil(t, err)
 }
+
+func TestBaseAccountJSON(t *testing.T) {
+	addr := sdk.Address([]byte("input"))
+	acc := NewBaseAccountWithAddress(addr)
+
+	// The address is bech32 encoded.
+	bz, err := json.Marshal(acc)
+	assert.Nil(t, err)
+	assert.Contains(t, string(bz), `"address":"cosmosaccaddr1d9h8qat5e4ehc5"`)
+
+	// Hex addresses are rejected.
+	var acc2 BaseAccount
+	err = json.Unmarshal([]byte(`{"address":"696E707574"}`), &acc2)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	wire "github.com/tendermint/go-wire"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var collectedFeesKey = []byte("collectedFees")
+
+// FeePool accumulates the fees deducted by the AnteHandler.
+type FeePool struct {
+
+	// The (unexposed) key used to access the store from the Context.
+	key sdk.StoreKey
+
+	// The wire codec for binary encoding/decoding of coins.
+	cdc *wire.Codec
+}
+
+// NewFeePool returns a new FeePool that keeps the collected fees
+// in the store of key.
+func NewFeePool(key sdk.StoreKey) FeePool {
+	return FeePool{
+		key: key,
+		cdc: wire.NewCodec(),
+	}
+}
+
+// GetCollectedFees returns the fees collected so far.
+func (fp FeePool) GetCollectedFees(ctx sdk.Context) sdk.Coins {
+	store := ctx.KVStore(fp.key)
+	bz := store.Get(collectedFeesKey)
+	if bz == nil {
+		return sdk.Coins{}
+	}
+	var fees sdk.Coins
+	err := fp.cdc.UnmarshalBinary(bz, &fees)
+	if err != nil {
+		panic(err)
+	}
+	return fees
+}
+
+// SetCollectedFees overwrites the collected fees,
+// e.g. to clear them once they have been distributed.
+func (fp FeePool) SetCollectedFees(ctx sdk.Context, fees sdk.Coins) {
+	bz, err := fp.cdc.MarshalBinary(fees)
+	if err != nil {
+		panic(err)
+	}
+	store := ctx.KVStore(fp.key)
+	store.Set(collectedFeesKey, bz)
+}
+
+// Adds fees to the collected fees.
+func (fp FeePool) addCollectedFees(ctx sdk.Context, fees sdk.Coins) sdk.Coins {
+	newFees := fp.GetCollectedFees(ctx).Plus(fees)
+	fp.SetCollectedFees(ctx, newFees)
+	return newFees
+}
@@ -4,21 +4,36 @@ import (
 	"fmt"
 	"reflect"
 
-	crypto "github.com/tendermint/go-crypto"
 	wire "github.com/tendermint/go-wire"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+var (
+	// The prefix of the keys of accounts, in the account store.
+	addressStoreKeyPrefix = []byte("account:")
+
+	// The key of the next account number, in the account store.
+	globalAccountNumberKey = []byte("globalAccountNumber")
+)
+
+// AddressStoreKey returns the key of the account of addr, in the
+// account store.
+func AddressStoreKey(addr sdk.Address) []byte {
+	return append(append([]byte{}, addressStoreKeyPrefix...), addr...)
+}
+
 // Implements sdk.AccountMapper.
 // This AccountMapper encodes/decodes accounts using the
-// go-wire (binary) encoding/decoding library.
+// go-wire (binary) encoding/decoding library.  Accounts are encoded
+// as sdk.Account, so that a store can hold several concrete account
+// types, e.g. vesting accounts next to the app's account type.
 type accountMapper struct {
 
 	// The (unexposed) key used to access the store from the Context.
 	key sdk.StoreKey
 
-	// The prototypical sdk.Account concrete type.
+	// The prototypical sdk.Account concrete type of new accounts.
 	proto sdk.Account
 
 	// The wire codec for binary encoding/decoding of accounts.
@@ -27,6 +42,8 @@ type accountMapper struct {
 
 // NewAccountMapper returns a new sdk.AccountMapper that
 // uses go-wire to (binary) encode and decode concrete sdk.Accounts.
+// Every concrete sdk.Account type that is stored, including the type
+// of proto, must be registered with the WireCodec().
 func NewAccountMapper(key sdk.StoreKey, proto sdk.Account) accountMapper {
 	cdc := wire.NewCodec()
 	return accountMapper{
@@ -52,16 +69,39 @@ func (am accountMapper) Seal() sealedAccountMapper {
 }
 
 // Implements sdk.AccountMapper.
-func (am accountMapper) NewAccountWithAddress(ctx sdk.Context, addr crypto.Address) sdk.Account {
+func (am accountMapper) NewAccountWithAddress(ctx sdk.Context, addr sdk.Address) sdk.Account {
 	acc := am.clonePrototype()
 	acc.SetAddress(addr)
+	acc.SetAccountNumber(am.GetNextAccountNumber(ctx))
 	return acc
 }
 
 // Implements sdk.AccountMapper.
-func (am accountMapper) GetAccount(ctx sdk.Context, addr crypto.Address) sdk.Account {
+// Account numbers start at 0 and are never reused, so an account that
+// is deleted and recreated gets a new one.
+func (am accountMapper) GetNextAccountNumber(ctx sdk.Context) int64 {
+	var accNumber int64
+	store := ctx.KVStore(am.key)
+	bz := store.Get(globalAccountNumberKey)
+	if bz != nil {
+		err := am.cdc.UnmarshalBinary(bz, &accNumber)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	bz, err := am.cdc.MarshalBinary(accNumber + 1)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(globalAccountNumberKey, bz)
+	return accNumber
+}
+
+// Implements sdk.AccountMapper.
+func (am accountMapper) GetAccount(ctx sdk.Context, addr sdk.Address) sdk.Account {
 	store := ctx.KVStore(am.key)
-	bz := store.Get(addr)
+	bz := store.Get(AddressStoreKey(addr))
 	if bz == nil {
 		return nil
 	}
@@ -74,27 +114,25 @@ func (am accountMapper) SetAccount(ctx sdk.Context, acc sdk.Account) {
 	addr := acc.GetAddress()
 	store := ctx.KVStore(am.key)
 	bz := am.encodeAccount(acc)
-	store.Set(addr, bz)
+	store.Set(AddressStoreKey(addr), bz)
 }
 
-//----------------------------------------
-// misc.
-
-func (am accountMapper) clonePrototypePtr() interface{} {
-	protoRt := reflect.TypeOf(am.proto)
-	if protoRt.Kind() == reflect.Ptr {
-		protoErt := protoRt.Elem()
-		if protoErt.Kind() != reflect.Struct {
-			panic("accountMapper requires a struct proto sdk.Account, or a pointer to one")
+// Implements sdk.AccountMapper.
+func (am accountMapper) IterateAccounts(ctx sdk.Context, process func(sdk.Account) (stop bool)) {
+	store := ctx.KVStore(am.key)
+	iter := store.Iterator(addressStoreKeyPrefix, sdk.PrefixEndBytes(addressStoreKeyPrefix))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		acc := am.decodeAccount(iter.Value())
+		if process(acc) {
+			return
 		}
-		protoRv := reflect.New(protoErt)
-		return protoRv.Interface()
-	} else {
-		protoRv := reflect.New(protoRt)
-		return protoRv.Interface()
 	}
 }
 
+//----------------------------------------
+// misc.
+
 // Creates a new struct (or pointer to struct) from am.proto.
 func (am accountMapper) clonePrototype() sdk.Account {
 	protoRt := reflect.TypeOf(am.proto)
@@ -120,24 +158,19 @@ func (am accountMapper) clonePrototype() sdk.Account {
 }
 
 func (am accountMapper) encodeAccount(acc sdk.Account) []byte {
-	bz, err := am.cdc.MarshalBinary(acc)
+	bz, err := am.cdc.MarshalBinary(&acc)
 	if err != nil {
 		panic(err)
 	}
 	return bz
 }
 
-func (am accountMapper) decodeAccount(bz []byte) sdk.Account {
-	accPtr := am.clonePrototypePtr()
-	err := am.cdc.UnmarshalBinary(bz, accPtr)
+func (am accountMapper) decodeAccount(bz []byte) (acc sdk.Account) {
+	err := am.cdc.UnmarshalBinary(bz, &acc)
 	if err != nil {
 		panic(err)
 	}
-	if reflect.ValueOf(am.proto).Kind() == reflect.Ptr {
-		return reflect.ValueOf(accPtr).Interface().(sdk.Account)
-	} else {
-		return reflect.ValueOf(accPtr).Elem().Interface().(sdk.Account)
-	}
+	return acc
 }
 
 //----------------------------------------
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"errors"
+
+	crypto "github.com/tendermint/go-crypto"
+	"golang.org/x/crypto/ripemd160"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Permissions of module accounts.
+const (
+	Minter = "minter" // may create coins
+	Burner = "burner" // may destroy coins
+)
+
+var _ sdk.Account = (*ModuleAccount)(nil)
+
+// ModuleAccount is an account owned by a module, e.g. a fee collector
+// or an escrow, rather than by a key.  It has no pubkey, so the
+// AnteHandler refuses it as a signer, and only the module's code moves
+// its coins, e.g. with the bank CoinMapper.
+type ModuleAccount struct {
+	BaseAccount
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// NewModuleAddress returns the address of the account of the module
+// name, the RIPEMD160 of "module/" and the name.  No key has it as its
+// address, except by a hash collision.
+func NewModuleAddress(name string) sdk.Address {
+	h := ripemd160.New()
+	h.Write([]byte("module/" + name))
+	return sdk.Address(h.Sum(nil))
+}
+
+// NewModuleAccount returns the account of the module name, with
+// permissions.  Apps set it, with an account number, e.g. at genesis,
+// unless the bank CoinMapper creates it on first use.
+func NewModuleAccount(name string, permissions ...string) *ModuleAccount {
+	return &ModuleAccount{
+		BaseAccount: NewBaseAccountWithAddress(NewModuleAddress(name)),
+		Name:        name,
+		Permissions: permissions,
+	}
+}
+
+// GetName returns the name of the module.
+func (ma ModuleAccount) GetName() string {
+	return ma.Name
+}
+
+// HasPermission returns true if the module account has permission.
+func (ma ModuleAccount) HasPermission(permission string) bool {
+	for _, p := range ma.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPubKey implements sdk.Account.
+// Module accounts have no pubkey, so it always errors.
+func (ma *ModuleAccount) SetPubKey(pubKey crypto.PubKey) error {
+	return errors.New("cannot set the pubkey of a module account")
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	crypto "github.com/tendermint/go-crypto"
+	wire "github.com/tendermint/go-wire"
+)
+
+func TestModuleAccount(t *testing.T) {
+	acc := NewModuleAccount("escrow", Burner)
+	assert.Equal(t, NewModuleAddress("escrow"), acc.GetAddress())
+	assert.Len(t, acc.GetAddress(), 20)
+	assert.NotEqual(t, NewModuleAddress("fees"), acc.GetAddress())
+	assert.Equal(t, "escrow", acc.GetName())
+
+	assert.True(t, acc.HasPermission(Burner))
+	assert.False(t, acc.HasPermission(Minter))
+
+	// No key owns a module account.
+	err := acc.SetPubKey(crypto.GenPrivKeyEd25519().PubKey())
+	assert.NotNil(t, err)
+	assert.Nil(t, acc.GetPubKey())
+}
+
+func TestModuleAccountWire(t *testing.T) {
+	cdc := wire.NewCodec()
+	RegisterWireBaseAccount(cdc)
+
+	var acc sdk.Account = NewModuleAccount("mint", Minter, Burner)
+	err := acc.SetCoins(sdk.Coins{sdk.NewCoin("atom", 10)})
+	assert.Nil(t, err)
+	bz, err := cdc.MarshalBinary(&acc)
+	assert.Nil(t, err)
+	var acc2 sdk.Account
+	err = cdc.UnmarshalBinary(bz, &acc2)
+	assert.Nil(t, err)
+	assert.Equal(t, acc, acc2)
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	crypto "github.com/tendermint/go-crypto"
+	wire "github.com/tendermint/go-wire"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// Type prefixes of the binary encodings, after those of go-crypto.
+const (
+	multisigThresholdPubKeyPrefix = 0x22
+	multisignaturePrefix          = 0x23
+)
+
+//----------------------------------------
+// PubKeyMultisigThreshold
+
+var _ crypto.PubKey = PubKeyMultisigThreshold{}
+
+// PubKeyMultisigThreshold is a k-of-n threshold pubkey.  A Multisignature
+// is valid for it if at least K of its PubKeys signed.  Its address is
+// derived from K and the member keys, in order, so the same members with
+// another threshold or order have another address.
+type PubKeyMultisigThreshold struct {
+	K       uint            `json:"threshold"`
+	PubKeys []crypto.PubKey `json:"pubkeys"`
+}
+
+// NewPubKeyMultisigThreshold returns a k-of-len(pubKeys) threshold
+// pubkey.  It panics if k is 0 or greater than len(pubKeys), or if a
+// pubkey is nil.
+func NewPubKeyMultisigThreshold(k uint, pubKeys []crypto.PubKey) PubKeyMultisigThreshold {
+	if k == 0 {
+		panic("threshold k of n multisignature: k must be greater than 0")
+	}
+	if int(k) > len(pubKeys) {
+		panic(fmt.Sprintf("threshold k of n multisignature: k %d exceeds n %d", k, len(pubKeys)))
+	}
+	for _, pubKey := range pubKeys {
+		if pubKey == nil {
+			panic("threshold k of n multisignature: nil pubkey")
+		}
+	}
+	return PubKeyMultisigThreshold{K: k, PubKeys: pubKeys}
+}
+
+// Address returns the RIPEMD160 of Bytes(), like ed25519 addresses.
+func (pk PubKeyMultisigThreshold) Address() crypto.Address {
+	h := ripemd160.New()
+	h.Write(pk.Bytes())
+	return crypto.Address(h.Sum(nil))
+}
+
+// Bytes returns the prefix, K, n and each length-prefixed member key.
+func (pk PubKeyMultisigThreshold) Bytes() []byte {
+	buf := []byte{multisigThresholdPubKeyPrefix}
+	buf = appendUvarint(buf, uint64(pk.K))
+	buf = appendUvarint(buf, uint64(len(pk.PubKeys)))
+	for _, pubKey := range pk.PubKeys {
+		buf = appendLengthPrefixed(buf, pubKey.Bytes())
+	}
+	return buf
+}
+
+// VerifyBytes returns true if sig is a Multisignature of msg by at
+// least K of the member keys, and every signature in it is valid.
+// A decoded key may bypass NewPubKeyMultisigThreshold, so a key with
+// a K of 0 or greater than n verifies nothing.
+func (pk PubKeyMultisigThreshold) VerifyBytes(msg []byte, sig crypto.Signature) bool {
+	multisig, ok := sig.(Multisignature)
+	if !ok {
+		return false
+	}
+	n := len(pk.PubKeys)
+	if pk.K == 0 || int(pk.K) > n {
+		return false
+	}
+	if len(multisig.BitArray) != bitArrayLen(n) || !multisig.bitsAfterUnset(n) {
+		return false
+	}
+	signers := multisig.NumSigners()
+	if signers < int(pk.K) || signers != len(multisig.Sigs) {
+		return false
+	}
+	j := 0
+	for i := 0; i < n; i++ {
+		if !multisig.HasSigner(i) {
+			continue
+		}
+		if !pk.PubKeys[i].VerifyBytes(msg, multisig.Sigs[j]) {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// Equals returns true if other is the same threshold pubkey.
+func (pk PubKeyMultisigThreshold) Equals(other crypto.PubKey) bool {
+	o, ok := other.(PubKeyMultisigThreshold)
+	return ok && bytes.Equal(pk.Bytes(), o.Bytes())
+}
+
+//----------------------------------------
+// Multisignature
+
+var _ crypto.Signature = Multisignature{}
+
+// Multisignature is the signature of a PubKeyMultisigThreshold.  It is
+// compact: BitArray has a bit per member key, set if the member signed,
+// and Sigs only holds the signatures of the members that did, in the
+// order of the keys.
+type Multisignature struct {
+	BitArray []byte             `json:"bit_array"`
+	Sigs     []crypto.Signature `json:"sigs"`
+}
+
+// NewMultisignature returns an empty Multisignature for n member keys.
+func NewMultisignature(n int) Multisignature {
+	return Multisignature{
+		BitArray: make([]byte, bitArrayLen(n)),
+	}
+}
+
+// AddSignature adds the signature of the member key at index, or
+// replaces it if the member already signed.  It panics if index is
+// out of range.
+func (ms *Multisignature) AddSignature(sig crypto.Signature, index int) {
+	pos := 0
+	for i := 0; i < index; i++ {
+		if ms.HasSigner(i) {
+			pos++
+		}
+	}
+	if ms.HasSigner(index) {
+		ms.Sigs[pos] = sig
+		return
+	}
+	ms.BitArray[index/8] |= 1 << uint(index%8)
+	ms.Sigs = append(ms.Sigs, nil)
+	copy(ms.Sigs[pos+1:], ms.Sigs[pos:])
+	ms.Sigs[pos] = sig
+}
+
+// AddSignatureFromPubKey adds the signature of pubKey, which must be
+// a member of multisigKey.
+func (ms *Multisignature) AddSignatureFromPubKey(sig crypto.Signature, pubKey crypto.PubKey, multisigKey PubKeyMultisigThreshold) error {
+	for i, member := range multisigKey.PubKeys {
+		if member.Equals(pubKey) {
+			ms.AddSignature(sig, i)
+			return nil
+		}
+	}
+	return fmt.Errorf("pubkey %X is not a member of the multisig key", pubKey.Bytes())
+}
+
+// HasSigner returns true if the member key at index signed.
+func (ms Multisignature) HasSigner(index int) bool {
+	if index < 0 || index/8 >= len(ms.BitArray) {
+		return false
+	}
+	return ms.BitArray[index/8]&(1<<uint(index%8)) != 0
+}
+
+// NumSigners returns the number of members that signed.
+func (ms Multisignature) NumSigners() int {
+	count := 0
+	for i := 0; i < len(ms.BitArray)*8; i++ {
+		if ms.HasSigner(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// bitsAfterUnset returns true if no bit of index n or more is set,
+// so that each Multisignature has a single encoding.
+func (ms Multisignature) bitsAfterUnset(n int) bool {
+	for i := n; i < len(ms.BitArray)*8; i++ {
+		if ms.HasSigner(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bytes returns the prefix, the bit array and each length-prefixed
+// signature.
+func (ms Multisignature) Bytes() []byte {
+	buf := []byte{multisignaturePrefix}
+	buf = appendLengthPrefixed(buf, ms.BitArray)
+	for _, sig := range ms.Sigs {
+		buf = appendLengthPrefixed(buf, sig.Bytes())
+	}
+	return buf
+}
+
+// IsZero returns true if no member signed.
+func (ms Multisignature) IsZero() bool {
+	return len(ms.Sigs) == 0
+}
+
+// Equals returns true if other is the same Multisignature.
+func (ms Multisignature) Equals(other crypto.Signature) bool {
+	o, ok := other.(Multisignature)
+	return ok && bytes.Equal(ms.Bytes(), o.Bytes())
+}
+
+//----------------------------------------
+// Encoding helpers
+
+// bitArrayLen returns the number of bytes of a bit array of n bits.
+func bitArrayLen(n int) int {
+	return (n + 7) / 8
+}
+
+func appendUvarint(buf []byte, x uint64) []byte {
+	var varint [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(varint[:], x)
+	return append(buf, varint[:n]...)
+}
+
+func appendLengthPrefixed(buf []byte, bz []byte) []byte {
+	buf = appendUvarint(buf, uint64(len(bz)))
+	return append(buf, bz...)
+}
+
+//----------------------------------------
+// Wire
+
+// RegisterWireMultisig registers the multisig pubkey and signature
+// types, which may be used wherever a crypto.PubKey or
+// crypto.Signature is, e.g. in a StdSignature or a BaseAccount.
+func RegisterWireMultisig(cdc *wire.Codec) {
+	cdc.RegisterConcrete(PubKeyMultisigThreshold{}, "cosmos-sdk/PubKeyMultisigThreshold", nil)
+	cdc.RegisterConcrete(Multisignature{}, "cosmos-sdk/Multisignature", nil)
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	crypto "github.com/tendermint/go-crypto"
+	wire "github.com/tendermint/go-wire"
+)
+
+func generateKeys(n int) ([]crypto.PrivKey, []crypto.PubKey) {
+	privKeys := make([]crypto.PrivKey, n)
+	pubKeys := make([]crypto.PubKey, n)
+	for i := 0; i < n; i++ {
+		privKeys[i] = crypto.GenPrivKeyEd25519()
+		pubKeys[i] = privKeys[i].PubKey()
+	}
+	return privKeys, pubKeys
+}
+
+func TestMultisigThreshold(t *testing.T) {
+	msg := []byte("treasury spend")
+	privKeys, pubKeys := generateKeys(3)
+	multisigKey := NewPubKeyMultisigThreshold(2, pubKeys)
+
+	// One signature is not enough.
+	multisig := NewMultisignature(3)
+	multisig.AddSignature(privKeys[2].Sign(msg), 2)
+	assert.False(t, multisigKey.VerifyBytes(msg, multisig))
+
+	// Two are, in any order.
+	err := multisig.AddSignatureFromPubKey(privKeys[0].Sign(msg), pubKeys[0], multisigKey)
+	assert.Nil(t, err)
+	assert.True(t, multisig.HasSigner(0))
+	assert.False(t, multisig.HasSigner(1))
+	assert.Equal(t, 2, multisig.NumSigners())
+	assert.True(t, multisigKey.VerifyBytes(msg, multisig))
+
+	// And so are three.
+	multisig.AddSignature(privKeys[1].Sign(msg), 1)
+	assert.True(t, multisigKey.VerifyBytes(msg, multisig))
+
+	// Every signature must be valid.
+	multisig.AddSignature(privKeys[1].Sign([]byte("other msg")), 1)
+	assert.Equal(t, 3, multisig.NumSigners())
+	assert.False(t, multisigKey.VerifyBytes(msg, multisig))
+
+	// Only members can sign.
+	outsider := crypto.GenPrivKeyEd25519()
+	err = multisig.AddSignatureFromPubKey(outsider.Sign(msg), outsider.PubKey(), multisigKey)
+	assert.NotNil(t, err)
+
+	// A plain signature is not a Multisignature.
+	assert.False(t, multisigKey.VerifyBytes(msg, privKeys[0].Sign(msg)))
+}
+
+func TestMultisignatureMalformed(t *testing.T) {
+	msg := []byte("treasury spend")
+	privKeys, pubKeys := generateKeys(3)
+	multisigKey := NewPubKeyMultisigThreshold(2, pubKeys)
+
+	valid := NewMultisignature(3)
+	valid.AddSignature(privKeys[0].Sign(msg), 0)
+	valid.AddSignature(privKeys[1].Sign(msg), 1)
+	assert.True(t, multisigKey.VerifyBytes(msg, valid))
+
+	cases := map[string]Multisignature{
+		"bit array too long": {
+			BitArray: []byte{0x03, 0x00},
+			Sigs:     valid.Sigs,
+		},
+		"bit beyond n": {
+			BitArray: []byte{0x0b},
+			Sigs:     valid.Sigs,
+		},
+		"more sigs than bits": {
+			BitArray: []byte{0x03},
+			Sigs:     append(valid.Sigs, privKeys[2].Sign(msg)),
+		},
+		"fewer sigs than bits": {
+			BitArray: []byte{0x07},
+			Sigs:     valid.Sigs,
+		},
+	}
+	for name, multisig := range cases {
+		assert.False(t, multisigKey.VerifyBytes(msg, multisig), name)
+	}
+}
+
+func TestMultisigThresholdInvalid(t *testing.T) {
+	msg := []byte("treasury spend")
+	privKeys, pubKeys := generateKeys(3)
+
+	// Keys decoded from wire or JSON skip NewPubKeyMultisigThreshold.
+	zeroKey := PubKeyMultisigThreshold{K: 0, PubKeys: pubKeys}
+	assert.False(t, zeroKey.VerifyBytes(msg, NewMultisignature(3)))
+	emptyKey := PubKeyMultisigThreshold{K: 0}
+	assert.False(t, emptyKey.VerifyBytes(msg, NewMultisignature(0)))
+
+	multisig := NewMultisignature(3)
+	for i, privKey := range privKeys {
+		multisig.AddSignature(privKey.Sign(msg), i)
+	}
+	tooHighKey := PubKeyMultisigThreshold{K: 4, PubKeys: pubKeys}
+	assert.False(t, tooHighKey.VerifyBytes(msg, multisig))
+}
+
+func TestMultisigThresholdAddress(t *testing.T) {
+	_, pubKeys := generateKeys(3)
+	multisigKey := NewPubKeyMultisigThreshold(2, pubKeys)
+	assert.Len(t, multisigKey.Address(), 20)
+
+	// The address depends on the threshold and the order of the keys.
+	other := NewPubKeyMultisigThreshold(3, pubKeys)
+	assert.NotEqual(t, multisigKey.Address(), other.Address())
+	reordered := NewPubKeyMultisigThreshold(2, []crypto.PubKey{pubKeys[1], pubKeys[0], pubKeys[2]})
+	assert.NotEqual(t, multisigKey.Address(), reordered.Address())
+	assert.False(t, multisigKey.Equals(reordered))
+	assert.True(t, multisigKey.Equals(NewPubKeyMultisigThreshold(2, pubKeys)))
+
+	assert.Panics(t, func() { NewPubKeyMultisigThreshold(0, pubKeys) })
+	assert.Panics(t, func() { NewPubKeyMultisigThreshold(4, pubKeys) })
+}
+
+func TestMultisigWire(t *testing.T) {
+	msg := []byte("treasury spend")
+	privKeys, pubKeys := generateKeys(3)
+	multisigKey := NewPubKeyMultisigThreshold(2, pubKeys)
+	multisig := NewMultisignature(3)
+	multisig.AddSignature(privKeys[0].Sign(msg), 0)
+	multisig.AddSignature(privKeys[2].Sign(msg), 2)
+
+	cdc := wire.NewCodec()
+	RegisterWireBaseAccount(cdc)
+
+	acc := NewBaseAccountWithAddress(multisigKey.Address().Bytes())
+	err := acc.SetPubKey(multisigKey)
+	assert.Nil(t, err)
+	bz, err := cdc.MarshalBinary(acc)
+	assert.Nil(t, err)
+	var acc2 BaseAccount
+	err = cdc.UnmarshalBinary(bz, &acc2)
+	assert.Nil(t, err)
+	assert.True(t, multisigKey.Equals(acc2.GetPubKey()))
+
+	var sig crypto.Signature = multisig
+	bz, err = cdc.MarshalBinary(&sig)
+	assert.Nil(t, err)
+	var sig2 crypto.Signature
+	err = cdc.UnmarshalBinary(bz, &sig2)
+	assert.Nil(t, err)
+	assert.True(t, multisig.Equals(sig2))
+	assert.True(t, acc2.GetPubKey().VerifyBytes(msg, sig2))
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// VestingAccount is an account whose original vesting coins unlock
+// over time.  Coins that are still vesting can't be sent or used to
+// pay fees, but coins received later are always spendable.
+type VestingAccount interface {
+	sdk.Account
+
+	// GetOriginalVesting returns the coins that were locked initially.
+	GetOriginalVesting() sdk.Coins
+
+	// GetVestedCoins returns the original vesting coins that are
+	// unlocked at blockTime.
+	GetVestedCoins(blockTime time.Time) sdk.Coins
+
+	// GetVestingCoins returns the original vesting coins that are
+	// still locked at blockTime.
+	GetVestingCoins(blockTime time.Time) sdk.Coins
+}
+
+// SpendableCoins returns the coins of acc that can be spent at
+// blockTime: all of them, unless acc is a VestingAccount.
+func SpendableCoins(acc sdk.Account, blockTime time.Time) sdk.Coins {
+	coins := acc.GetCoins()
+	vacc, ok := acc.(VestingAccount)
+	if !ok {
+		return coins
+	}
+	locked := vacc.GetVestingCoins(blockTime)
+
+	var spendable sdk.Coins
+	for _, coin := range coins {
+		amount := coin.Amount.Sub(locked.AmountOf(coin.Denom))
+		if amount.Sign() > 0 {
+			spendable = append(spendable, sdk.Coin{Denom: coin.Denom, Amount: amount})
+		}
+	}
+	return spendable
+}
+
+//----------------------------------------
+// BaseVestingAccount
+
+// BaseVestingAccount is the common part of the vesting accounts.
+// Times are unix times in seconds, like abci.Header.Time.
+type BaseVestingAccount struct {
+	BaseAccount
+	OriginalVesting sdk.Coins `json:"original_vesting"`
+	EndTime         int64     `json:"end_time"` // when all coins are vested
+}
+
+// GetOriginalVesting implements VestingAccount.
+func (bva BaseVestingAccount) GetOriginalVesting() sdk.Coins {
+	return bva.OriginalVesting
+}
+
+// GetEndTime returns the time when all coins are vested.
+func (bva BaseVestingAccount) GetEndTime() int64 {
+	return bva.EndTime
+}
+
+// vestingCoins returns the original vesting coins that aren't vested.
+func (bva BaseVestingAccount) vestingCoins(vested sdk.Coins) sdk.Coins {
+	return bva.OriginalVesting.Minus(vested)
+}
+
+//----------------------------------------
+// ContinuousVestingAccount
+
+var _ VestingAccount = (*ContinuousVestingAccount)(nil)
+
+// ContinuousVestingAccount vests its coins linearly from StartTime to
+// EndTime.
+type ContinuousVestingAccount struct {
+	BaseVestingAccount
+	StartTime int64 `json:"start_time"`
+}
+
+// NewContinuousVestingAccount returns an account holding coins, which
+// vest linearly from startTime to endTime.
+func NewContinuousVestingAccount(addr sdk.Address, coins sdk.Coins, startTime, endTime int64) *ContinuousVestingAccount {
+	baseAcc := NewBaseAccountWithAddress(addr)
+	baseAcc.Coins = coins
+	return &ContinuousVestingAccount{
+		BaseVestingAccount: BaseVestingAccount{
+			BaseAccount:     baseAcc,
+			OriginalVesting: coins,
+			EndTime:         endTime,
+		},
+		StartTime: startTime,
+	}
+}
+
+// GetStartTime returns the time when coins start to vest.
+func (cva ContinuousVestingAccount) GetStartTime() int64 {
+	return cva.StartTime
+}
+
+// GetVestedCoins implements VestingAccount.
+// The vested amount of each denom is rounded down.
+func (cva ContinuousVestingAccount) GetVestedCoins(blockTime time.Time) sdk.Coins {
+	t := blockTime.Unix()
+	if t <= cva.StartTime {
+		return nil
+	}
+	if t >= cva.EndTime {
+		return cva.OriginalVesting
+	}
+
+	elapsed, duration := t-cva.StartTime, cva.EndTime-cva.StartTime
+	var vested sdk.Coins
+	for _, coin := range cva.OriginalVesting {
+		amount := coin.Amount.MulRaw(elapsed).DivRaw(duration)
+		if !amount.IsZero() {
+			vested = append(vested, sdk.Coin{Denom: coin.Denom, Amount: amount})
+		}
+	}
+	return vested
+}
+
+// GetVestingCoins implements VestingAccount.
+func (cva ContinuousVestingAccount) GetVestingCoins(blockTime time.Time) sdk.Coins {
+	return cva.vestingCoins(cva.GetVestedCoins(blockTime))
+}
+
+//----------------------------------------
+// DelayedVestingAccount
+
+var _ VestingAccount = (*DelayedVestingAccount)(nil)
+
+// DelayedVestingAccount vests all of its coins at EndTime (a cliff).
+type DelayedVestingAccount struct {
+	BaseVestingAccount
+}
+
+// NewDelayedVestingAccount returns an account holding coins, which
+// all vest at endTime.
+func NewDelayedVestingAccount(addr sdk.Address, coins sdk.Coins, endTime int64) *DelayedVestingAccount {
+	baseAcc := NewBaseAccountWithAddress(addr)
+	baseAcc.Coins = coins
+	return &DelayedVestingAccount{
+		BaseVestingAccount: BaseVestingAccount{
+			BaseAccount:     baseAcc,
+			OriginalVesting: coins,
+			EndTime:         endTime,
+		},
+	}
+}
+
+// GetVestedCoins implements VestingAccount.
+func (dva DelayedVestingAccount) GetVestedCoins(blockTime time.Time) sdk.Coins {
+	if blockTime.Unix() >= dva.EndTime {
+		return dva.OriginalVesting
+	}
+	return nil
+}
+
+// GetVestingCoins implements VestingAccount.
+func (dva DelayedVestingAccount) GetVestingCoins(blockTime time.Time) sdk.Coins {
+	return dva.vestingCoins(dva.GetVestedCoins(blockTime))
+}
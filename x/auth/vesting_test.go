@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+	crypto "github.com/tendermint/go-crypto"
+	wire "github.com/tendermint/go-wire"
+)
+
+func TestContinuousVestingAccount(t *testing.T) {
+	addr := sdk.NewAddress(crypto.GenPrivKeyEd25519().PubKey())
+	origCoins := sdk.Coins{sdk.NewCoin("atom", 100), sdk.NewCoin("eth", 3)}
+	acc := NewContinuousVestingAccount(addr, origCoins, 1000, 2000)
+
+	cases := []struct {
+		blockTime int64
+		vested    sdk.Coins
+	}{
+		{0, nil},
+		{1000, nil},
+		{1001, nil}, // rounded down
+		{1250, sdk.Coins{sdk.NewCoin("atom", 25)}},
+		{1500, sdk.Coins{sdk.NewCoin("atom", 50), sdk.NewCoin("eth", 1)}},
+		{1999, sdk.Coins{sdk.NewCoin("atom", 99), sdk.NewCoin("eth", 2)}},
+		{2000, origCoins},
+		{3000, origCoins},
+	}
+	for _, tc := range cases {
+		blockTime := time.Unix(tc.blockTime, 0)
+		vested := acc.GetVestedCoins(blockTime)
+		assert.True(t, tc.vested.IsEqual(vested), "%d: %v", tc.blockTime, vested)
+		assert.True(t, origCoins.IsEqual(vested.Plus(acc.GetVestingCoins(blockTime))), "%d", tc.blockTime)
+		assert.True(t, tc.vested.IsEqual(SpendableCoins(acc, blockTime)), "%d", tc.blockTime)
+	}
+}
+
+func TestDelayedVestingAccount(t *testing.T) {
+	addr := sdk.NewAddress(crypto.GenPrivKeyEd25519().PubKey())
+	origCoins := sdk.Coins{sdk.NewCoin("atom", 100)}
+	acc := NewDelayedVestingAccount(addr, origCoins, 2000)
+
+	assert.Nil(t, acc.GetVestedCoins(time.Unix(1999, 0)))
+	assert.Equal(t, origCoins, acc.GetVestingCoins(time.Unix(1999, 0)))
+	assert.Nil(t, SpendableCoins(acc, time.Unix(1999, 0)))
+
+	assert.Equal(t, origCoins, acc.GetVestedCoins(time.Unix(2000, 0)))
+	assert.True(t, acc.GetVestingCoins(time.Unix(2000, 0)).IsZero())
+	assert.Equal(t, origCoins, SpendableCoins(acc, time.Unix(2000, 0)))
+}
+
+func TestSpendableCoins(t *testing.T) {
+	addr := sdk.NewAddress(crypto.GenPrivKeyEd25519().PubKey())
+	acc := NewDelayedVestingAccount(addr, sdk.Coins{sdk.NewCoin("atom", 100)}, 2000)
+	before := time.Unix(1000, 0)
+
+	// Received coins are spendable right away.
+	acc.SetCoins(sdk.Coins{sdk.NewCoin("atom", 130), sdk.NewCoin("eth", 5)})
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 30), sdk.NewCoin("eth", 5)}, SpendableCoins(acc, before))
+
+	// Spent coins come out of the vested or received ones, so an
+	// account can't hold less than its still vesting coins.
+	acc.SetCoins(sdk.Coins{sdk.NewCoin("atom", 80)})
+	assert.Nil(t, SpendableCoins(acc, before))
+
+	// Other accounts can spend all of their coins.
+	baseAcc := NewBaseAccountWithAddress(addr)
+	baseAcc.SetCoins(sdk.Coins{sdk.NewCoin("atom", 10)})
+	assert.Equal(t, sdk.Coins{sdk.NewCoin("atom", 10)}, SpendableCoins(&baseAcc, before))
+}
+
+func TestVestingAccountWire(t *testing.T) {
+	cdc := wire.NewCodec()
+	RegisterWireBaseAccount(cdc)
+
+	addr := sdk.NewAddress(crypto.GenPrivKeyEd25519().PubKey())
+	coins := sdk.Coins{sdk.NewCoin("atom", 100)}
+	accs := []sdk.Account{
+		NewContinuousVestingAccount(addr, coins, 1000, 2000),
+		NewDelayedVestingAccount(addr, coins, 2000),
+	}
+	for _, acc := range accs {
+		bz, err := cdc.MarshalBinary(&acc)
+		assert.Nil(t, err)
+		var acc2 sdk.Account
+		err = cdc.UnmarshalBinary(bz, &acc2)
+		assert.Nil(t, err)
+		assert.Equal(t, acc, acc2)
+		_, ok := acc2.(VestingAccount)
+		assert.True(t, ok)
+	}
+}
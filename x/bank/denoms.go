@@ -0,0 +1,90 @@
+package bank
+
+import (
+	wire "github.com/tendermint/go-wire"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func denomKey(base string) []byte {
+	return append([]byte("denom:"), base...)
+}
+
+func displayDenomKey(display string) []byte {
+	return append([]byte("displayDenom:"), display...)
+}
+
+// DenomMapper stores the DenomMetadata of the denoms of the chain,
+// which are usually set at genesis.
+type DenomMapper struct {
+
+	// The (unexposed) key used to access the store from the Context.
+	key sdk.StoreKey
+
+	// The wire codec for binary encoding/decoding of metadata.
+	cdc *wire.Codec
+}
+
+// NewDenomMapper returns a new DenomMapper that keeps the metadata
+// in the store of key.
+func NewDenomMapper(key sdk.StoreKey) DenomMapper {
+	return DenomMapper{
+		key: key,
+		cdc: wire.NewCodec(),
+	}
+}
+
+// SetDenomMetadata stores meta, indexed by its base and display denoms.
+// It panics if meta is invalid, or if one of its denoms is already
+// used by another entry, as either its base or display denom, so that
+// every denom names a single entry.
+func (dm DenomMapper) SetDenomMetadata(ctx sdk.Context, meta sdk.DenomMetadata) {
+	if err := meta.ValidateBasic(); err != nil {
+		panic(err)
+	}
+	store := ctx.KVStore(dm.key)
+	if base := store.Get(displayDenomKey(meta.Display)); base != nil && string(base) != meta.Base {
+		panic("display denom " + meta.Display + " is already used by " + string(base))
+	}
+	if meta.Display != meta.Base && store.Has(denomKey(meta.Display)) {
+		panic("display denom " + meta.Display + " is already a base denom")
+	}
+	if base := store.Get(displayDenomKey(meta.Base)); base != nil && string(base) != meta.Base {
+		panic("base denom " + meta.Base + " is already the display denom of " + string(base))
+	}
+	if old, ok := dm.GetDenomMetadata(ctx, meta.Base); ok && old.Base == meta.Base {
+		store.Delete(displayDenomKey(old.Display))
+	}
+	bz, err := dm.cdc.MarshalBinary(meta)
+	if err != nil {
+		panic(err)
+	}
+	store.Set(denomKey(meta.Base), bz)
+	store.Set(displayDenomKey(meta.Display), []byte(meta.Base))
+}
+
+// GetDenomMetadata returns the metadata of a base or display denom.
+func (dm DenomMapper) GetDenomMetadata(ctx sdk.Context, denom string) (meta sdk.DenomMetadata, ok bool) {
+	store := ctx.KVStore(dm.key)
+	bz := store.Get(denomKey(denom))
+	if bz == nil {
+		base := store.Get(displayDenomKey(denom))
+		if base == nil {
+			return meta, false
+		}
+		bz = store.Get(denomKey(string(base)))
+	}
+	err := dm.cdc.UnmarshalBinary(bz, &meta)
+	if err != nil {
+		panic(err)
+	}
+	return meta, true
+}
+
+// DenomLookup returns a sdk.DenomLookup backed by the store of ctx,
+// e.g. for ParseDisplayCoins.
+func (dm DenomMapper) DenomLookup(ctx sdk.Context) sdk.DenomLookup {
+	return func(denom string) (sdk.DenomMetadata, bool) {
+		return dm.GetDenomMetadata(ctx, denom)
+	}
+}
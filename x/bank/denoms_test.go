@@ -0,0 +1,71 @@
+package bank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	abci "github.com/tendermint/abci/types"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// setupMultiStore returns a multistore with an IAVL store at key.
+func setupMultiStore(key sdk.StoreKey) sdk.MultiStore {
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, db)
+	ms.LoadLatestVersion()
+	return ms
+}
+
+func TestDenomMapper(t *testing.T) {
+	key := sdk.NewKVStoreKey("main")
+	ctx := sdk.NewContext(setupMultiStore(key), abci.Header{}, false, nil)
+	dm := NewDenomMapper(key)
+
+	atom := sdk.DenomMetadata{Base: "uatom", Display: "atom", Exponent: 6}
+	dm.SetDenomMetadata(ctx, atom)
+	photon := sdk.DenomMetadata{Base: "photon", Display: "photon"}
+	dm.SetDenomMetadata(ctx, photon)
+
+	// Entries are found by their base and display denoms.
+	for _, denom := range []string{"uatom", "atom"} {
+		meta, ok := dm.GetDenomMetadata(ctx, denom)
+		assert.True(t, ok, denom)
+		assert.Equal(t, atom, meta, denom)
+	}
+	meta, ok := dm.GetDenomMetadata(ctx, "photon")
+	assert.True(t, ok)
+	assert.Equal(t, photon, meta)
+	_, ok = dm.GetDenomMetadata(ctx, "matom")
+	assert.False(t, ok)
+	_, ok = dm.DenomLookup(ctx)("matom")
+	assert.False(t, ok)
+
+	// An entry can be updated, which frees its old display denom.
+	atom.Display, atom.Exponent = "matom", 3
+	dm.SetDenomMetadata(ctx, atom)
+	_, ok = dm.GetDenomMetadata(ctx, "atom")
+	assert.False(t, ok)
+	meta, ok = dm.DenomLookup(ctx)("matom")
+	assert.True(t, ok)
+	assert.Equal(t, atom, meta)
+
+	// A denom names a single entry, as its base or display denom.
+	collisions := []sdk.DenomMetadata{
+		{Base: "ustake", Display: "matom", Exponent: 6},  // display of uatom
+		{Base: "ustake", Display: "uatom", Exponent: 6},  // base of uatom
+		{Base: "matom", Display: "stake", Exponent: 6},   // base is display of uatom
+		{Base: "photon", Display: "uatom", Exponent: 6},  // existing entry
+		{Base: "uatom", Display: "photon", Exponent: 6},  // existing entry
+		{Base: "ustake", Display: "stake", Exponent: 19}, // invalid
+	}
+	for _, meta := range collisions {
+		assert.Panics(t, func() { dm.SetDenomMetadata(ctx, meta) }, "%v", meta)
+	}
+	meta, ok = dm.GetDenomMetadata(ctx, "uatom")
+	assert.True(t, ok)
+	assert.Equal(t, atom, meta)
+}
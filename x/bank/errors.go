@@ -7,8 +7,11 @@ import (
 
 type CodeType = sdk.CodeType
 
+// DefaultCodespace is the codespace of the errors of this module.
+// Apps must register it with BaseApp.RegisterCodespace.
+const DefaultCodespace sdk.CodespaceType = 2
+
 const (
-	// Coin errors reserve 100 ~ 199.
 	CodeInvalidInput      CodeType = 101
 	CodeInvalidOutput     CodeType = 102
 	CodeInvalidAddress    CodeType = 103
@@ -95,5 +98,5 @@ func msgOrDefaultMsg(msg string, code CodeType) string {
 
 func newError(code CodeType, msg string) sdk.Error {
 	msg = msgOrDefaultMsg(msg, code)
-	return sdk.NewError(code, msg)
+	return sdk.NewError(DefaultCodespace, code, msg)
 }
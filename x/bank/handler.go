@@ -11,7 +11,7 @@ import (
 func NewHandler(am sdk.AccountMapper) sdk.Handler {
 
 	return func(ctx sdk.Context, msg sdk.Msg) sdk.Result {
-		cm := CoinMapper{am}
+		cm := CoinMapper{am: am}
 		switch msg := msg.(type) {
 		case SendMsg:
 			return handleSendMsg(ctx, cm, msg)
@@ -4,34 +4,44 @@ import (
 	"fmt"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	crypto "github.com/tendermint/go-crypto"
+	"github.com/cosmos/cosmos-sdk/x/auth"
 )
 
 // CoinMapper manages transfers between accounts
 type CoinMapper struct {
-	am sdk.AccountMapper
+	am      sdk.AccountMapper
+	modules map[string][]string // module name -> permissions
+}
+
+// NewCoinMapper returns a CoinMapper of the accounts of am, e.g. for
+// modules that move the coins of their module accounts.  modules maps
+// the names of the modules to the permissions of their accounts, which
+// are created on first use.
+func NewCoinMapper(am sdk.AccountMapper, modules map[string][]string) CoinMapper {
+	return CoinMapper{am, modules}
 }
 
 // SubtractCoins subtracts amt from the coins at the addr.
-func (cm CoinMapper) SubtractCoins(ctx sdk.Context, addr crypto.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+func (cm CoinMapper) SubtractCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
 	acc := cm.am.GetAccount(ctx, addr)
 	if acc == nil {
 		return amt, sdk.ErrUnrecognizedAddress(addr)
 	}
 
-	coins := acc.GetCoins()
-	newCoins := coins.Minus(amt)
-	if !newCoins.IsNotNegative() {
-		return amt, ErrInsufficientCoins(fmt.Sprintf("%s < %s", coins, amt))
+	// Still vesting coins of an auth.VestingAccount can't be sent.
+	spendable := auth.SpendableCoins(acc, ctx.BlockTime())
+	if !spendable.IsGTE(amt) {
+		return amt, ErrInsufficientCoins(fmt.Sprintf("%s < %s", spendable, amt))
 	}
 
+	newCoins := acc.GetCoins().Minus(amt)
 	acc.SetCoins(newCoins)
 	cm.am.SetAccount(ctx, acc)
 	return newCoins, nil
 }
 
 // AddCoins adds amt to the coins at the addr.
-func (cm CoinMapper) AddCoins(ctx sdk.Context, addr crypto.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+func (cm CoinMapper) AddCoins(ctx sdk.Context, addr sdk.Address, amt sdk.Coins) (sdk.Coins, sdk.Error) {
 	acc := cm.am.GetAccount(ctx, addr)
 	if acc == nil {
 		acc = cm.am.NewAccountWithAddress(ctx, addr)
@@ -44,3 +54,87 @@ func (cm CoinMapper) AddCoins(ctx sdk.Context, addr crypto.Address, amt sdk.Coin
 	cm.am.SetAccount(ctx, acc)
 	return newCoins, nil
 }
+
+// SendCoinsFromModuleToAccount sends amt from the account of the
+// module name to the account at addr.
+func (cm CoinMapper) SendCoinsFromModuleToAccount(ctx sdk.Context, name string, addr sdk.Address, amt sdk.Coins) sdk.Error {
+	macc, err := cm.getModuleAccount(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = cm.SubtractCoins(ctx, macc.GetAddress(), amt)
+	if err != nil {
+		return err
+	}
+	_, err = cm.AddCoins(ctx, addr, amt)
+	return err
+}
+
+// SendCoinsFromAccountToModule sends amt from the account at addr to
+// the account of the module name.
+func (cm CoinMapper) SendCoinsFromAccountToModule(ctx sdk.Context, addr sdk.Address, name string, amt sdk.Coins) sdk.Error {
+	macc, err := cm.getModuleAccount(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = cm.SubtractCoins(ctx, addr, amt)
+	if err != nil {
+		return err
+	}
+	_, err = cm.AddCoins(ctx, macc.GetAddress(), amt)
+	return err
+}
+
+// MintCoins creates amt in the account of the module name, which must
+// have the auth.Minter permission.
+func (cm CoinMapper) MintCoins(ctx sdk.Context, name string, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	macc, err := cm.getModuleAccount(ctx, name)
+	if err != nil {
+		return amt, err
+	}
+	if !macc.HasPermission(auth.Minter) {
+		return amt, sdk.ErrUnauthorized(fmt.Sprintf("module %s can't mint coins", name))
+	}
+	return cm.AddCoins(ctx, macc.GetAddress(), amt)
+}
+
+// BurnCoins destroys amt from the account of the module name, which
+// must have the auth.Burner permission.
+func (cm CoinMapper) BurnCoins(ctx sdk.Context, name string, amt sdk.Coins) (sdk.Coins, sdk.Error) {
+	macc, err := cm.getModuleAccount(ctx, name)
+	if err != nil {
+		return amt, err
+	}
+	if !macc.HasPermission(auth.Burner) {
+		return amt, sdk.ErrUnauthorized(fmt.Sprintf("module %s can't burn coins", name))
+	}
+	return cm.SubtractCoins(ctx, macc.GetAddress(), amt)
+}
+
+// getModuleAccount returns the account of the module name, which the
+// app set or which is created with the permissions of the module.
+// Coins sent to the module address before then made a plain account,
+// which becomes the module account, keeping its coins and number.
+func (cm CoinMapper) getModuleAccount(ctx sdk.Context, name string) (*auth.ModuleAccount, sdk.Error) {
+	acc := cm.am.GetAccount(ctx, auth.NewModuleAddress(name))
+	if macc, ok := acc.(*auth.ModuleAccount); ok {
+		return macc, nil
+	}
+	permissions, ok := cm.modules[name]
+	if !ok {
+		if acc == nil {
+			return nil, ErrUnknownAddress(fmt.Sprintf("no account for module %s", name))
+		}
+		return nil, ErrInvalidAddress(fmt.Sprintf("account of module %s is not a module account", name))
+	}
+
+	macc := auth.NewModuleAccount(name, permissions...)
+	if acc == nil {
+		macc.SetAccountNumber(cm.am.GetNextAccountNumber(ctx))
+	} else {
+		macc.SetAccountNumber(acc.GetAccountNumber())
+		macc.SetCoins(acc.GetCoins())
+	}
+	cm.am.SetAccount(ctx, macc)
+	return macc, nil
+}
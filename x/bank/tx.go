@@ -21,7 +21,7 @@ func NewSendMsg(in []Input, out []Output) SendMsg {
 }
 
 // Implements Msg.
-func (msg SendMsg) Type() string { return "bank" } // TODO: "bank/send"
+func (msg SendMsg) Type() string { return "bank/send" }
 
 // Implements Msg.
 func (msg SendMsg) ValidateBasic() sdk.Error {
@@ -65,16 +65,16 @@ func (msg SendMsg) Get(key interface{}) (value interface{}) {
 
 // Implements Msg.
 func (msg SendMsg) GetSignBytes() []byte {
-	b, err := json.Marshal(msg) // XXX: ensure some canonical form
+	b, err := json.Marshal(msg)
 	if err != nil {
 		panic(err)
 	}
-	return b
+	return sdk.MustSortJSON(b)
 }
 
 // Implements Msg.
-func (msg SendMsg) GetSigners() []crypto.Address {
-	addrs := make([]crypto.Address, len(msg.Inputs))
+func (msg SendMsg) GetSigners() []sdk.Address {
+	addrs := make([]sdk.Address, len(msg.Inputs))
 	for i, in := range msg.Inputs {
 		addrs[i] = in.Address
 	}
@@ -86,17 +86,17 @@ func (msg SendMsg) GetSigners() []crypto.Address {
 
 // IssueMsg - high level transaction of the coin module
 type IssueMsg struct {
-	Banker  crypto.Address `json:"banker"`
-	Outputs []Output       `json:"outputs"`
+	Banker  sdk.Address `json:"banker"`
+	Outputs []Output    `json:"outputs"`
 }
 
 // NewIssueMsg - construct arbitrary multi-in, multi-out send msg.
-func NewIssueMsg(banker crypto.Address, out []Output) IssueMsg {
+func NewIssueMsg(banker sdk.Address, out []Output) IssueMsg {
 	return IssueMsg{Banker: banker, Outputs: out}
 }
 
 // Implements Msg.
-func (msg IssueMsg) Type() string { return "bank" } // TODO: "bank/send"
+func (msg IssueMsg) Type() string { return "bank/issue" }
 
 // Implements Msg.
 func (msg IssueMsg) ValidateBasic() sdk.Error {
@@ -123,25 +123,25 @@ func (msg IssueMsg) Get(key interface{}) (value interface{}) {
 
 // Implements Msg.
 func (msg IssueMsg) GetSignBytes() []byte {
-	b, err := json.Marshal(msg) // XXX: ensure some canonical form
+	b, err := json.Marshal(msg)
 	if err != nil {
 		panic(err)
 	}
-	return b
+	return sdk.MustSortJSON(b)
 }
 
 // Implements Msg.
-func (msg IssueMsg) GetSigners() []crypto.Address {
-	return []crypto.Address{msg.Banker}
+func (msg IssueMsg) GetSigners() []sdk.Address {
+	return []sdk.Address{msg.Banker}
 }
 
 //----------------------------------------
 // Input
 
 type Input struct {
-	Address  crypto.Address `json:"address"`
-	Coins    sdk.Coins      `json:"coins"`
-	Sequence int64          `json:"sequence"`
+	Address  sdk.Address `json:"address"`
+	Coins    sdk.Coins   `json:"coins"`
+	Sequence int64       `json:"sequence"`
 
 	signature crypto.Signature
 }
@@ -168,7 +168,7 @@ func (in Input) String() string {
 }
 
 // NewInput - create a transaction input, used with SendMsg
-func NewInput(addr crypto.Address, coins sdk.Coins) Input {
+func NewInput(addr sdk.Address, coins sdk.Coins) Input {
 	input := Input{
 		Address: addr,
 		Coins:   coins,
@@ -177,7 +177,7 @@ func NewInput(addr crypto.Address, coins sdk.Coins) Input {
 }
 
 // NewInputWithSequence - create a transaction input, used with SendMsg
-func NewInputWithSequence(addr crypto.Address, coins sdk.Coins, seq int64) Input {
+func NewInputWithSequence(addr sdk.Address, coins sdk.Coins, seq int64) Input {
 	input := NewInput(addr, coins)
 	input.Sequence = seq
 	return input
@@ -187,8 +187,8 @@ func NewInputWithSequence(addr crypto.Address, coins sdk.Coins, seq int64) Input
 // Output
 
 type Output struct {
-	Address crypto.Address `json:"address"`
-	Coins   sdk.Coins      `json:"coins"`
+	Address sdk.Address `json:"address"`
+	Coins   sdk.Coins   `json:"coins"`
 }
 
 // ValidateBasic - validate transaction output
@@ -206,11 +206,11 @@ func (out Output) ValidateBasic() sdk.Error {
 }
 
 func (out Output) String() string {
-	return fmt.Sprintf("Output{%X,%v}", out.Address, out.Coins)
+	return fmt.Sprintf("Output{%v,%v}", out.Address, out.Coins)
 }
 
 // NewOutput - create a transaction output, used with SendMsg
-func NewOutput(addr crypto.Address, coins sdk.Coins) Output {
+func NewOutput(addr sdk.Address, coins sdk.Coins) Output {
 	output := Output{
 		Address: addr,
 		Coins:   coins,
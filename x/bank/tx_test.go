@@ -5,24 +5,22 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	crypto "github.com/tendermint/go-crypto"
-
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 func TestInputValidation(t *testing.T) {
-	addr1 := crypto.Address([]byte{1, 2})
-	addr2 := crypto.Address([]byte{7, 8})
-	someCoins := sdk.Coins{{"atom", 123}}
-	multiCoins := sdk.Coins{{"atom", 123}, {"eth", 20}}
+	addr1 := sdk.Address([]byte{1, 2})
+	addr2 := sdk.Address([]byte{7, 8})
+	someCoins := sdk.Coins{sdk.NewCoin("atom", 123)}
+	multiCoins := sdk.Coins{sdk.NewCoin("atom", 123), sdk.NewCoin("eth", 20)}
 
-	var emptyAddr crypto.Address
+	var emptyAddr sdk.Address
 	emptyCoins := sdk.Coins{}
-	emptyCoins2 := sdk.Coins{{"eth", 0}}
-	someEmptyCoins := sdk.Coins{{"eth", 10}, {"atom", 0}}
-	minusCoins := sdk.Coins{{"eth", -34}}
-	someMinusCoins := sdk.Coins{{"atom", 20}, {"eth", -34}}
-	unsortedCoins := sdk.Coins{{"eth", 1}, {"atom", 1}}
+	emptyCoins2 := sdk.Coins{sdk.NewCoin("eth", 0)}
+	someEmptyCoins := sdk.Coins{sdk.NewCoin("eth", 10), sdk.NewCoin("atom", 0)}
+	minusCoins := sdk.Coins{sdk.NewCoin("eth", -34)}
+	someMinusCoins := sdk.Coins{sdk.NewCoin("atom", 20), sdk.NewCoin("eth", -34)}
+	unsortedCoins := sdk.Coins{sdk.NewCoin("eth", 1), sdk.NewCoin("atom", 1)}
 
 	cases := []struct {
 		valid bool
@@ -55,18 +53,18 @@ func TestInputValidation(t *testing.T) {
 }
 
 func TestOutputValidation(t *testing.T) {
-	addr1 := crypto.Address([]byte{1, 2})
-	addr2 := crypto.Address([]byte{7, 8})
-	someCoins := sdk.Coins{{"atom", 123}}
-	multiCoins := sdk.Coins{{"atom", 123}, {"eth", 20}}
+	addr1 := sdk.Address([]byte{1, 2})
+	addr2 := sdk.Address([]byte{7, 8})
+	someCoins := sdk.Coins{sdk.NewCoin("atom", 123)}
+	multiCoins := sdk.Coins{sdk.NewCoin("atom", 123), sdk.NewCoin("eth", 20)}
 
-	var emptyAddr crypto.Address
+	var emptyAddr sdk.Address
 	emptyCoins := sdk.Coins{}
-	emptyCoins2 := sdk.Coins{{"eth", 0}}
-	someEmptyCoins := sdk.Coins{{"eth", 10}, {"atom", 0}}
-	minusCoins := sdk.Coins{{"eth", -34}}
-	someMinusCoins := sdk.Coins{{"atom", 20}, {"eth", -34}}
-	unsortedCoins := sdk.Coins{{"eth", 1}, {"atom", 1}}
+	emptyCoins2 := sdk.Coins{sdk.NewCoin("eth", 0)}
+	someEmptyCoins := sdk.Coins{sdk.NewCoin("eth", 10), sdk.NewCoin("atom", 0)}
+	minusCoins := sdk.Coins{sdk.NewCoin("eth", -34)}
+	someMinusCoins := sdk.Coins{sdk.NewCoin("atom", 20), sdk.NewCoin("eth", -34)}
+	unsortedCoins := sdk.Coins{sdk.NewCoin("eth", 1), sdk.NewCoin("atom", 1)}
 
 	cases := []struct {
 		valid bool
@@ -98,12 +96,12 @@ func TestOutputValidation(t *testing.T) {
 
 func TestSendMsgValidation(t *testing.T) {
 
-	addr1 := crypto.Address([]byte{1, 2})
-	addr2 := crypto.Address([]byte{7, 8})
-	atom123 := sdk.Coins{{"atom", 123}}
-	atom124 := sdk.Coins{{"atom", 124}}
-	eth123 := sdk.Coins{{"eth", 123}}
-	atom123eth123 := sdk.Coins{{"atom", 123}, {"eth", 123}}
+	addr1 := sdk.Address([]byte{1, 2})
+	addr2 := sdk.Address([]byte{7, 8})
+	atom123 := sdk.Coins{sdk.NewCoin("atom", 123)}
+	atom124 := sdk.Coins{sdk.NewCoin("atom", 124)}
+	eth123 := sdk.Coins{sdk.NewCoin("eth", 123)}
+	atom123eth123 := sdk.Coins{sdk.NewCoin("atom", 123), sdk.NewCoin("eth", 123)}
 
 	input1 := NewInput(addr1, atom123)
 	input2 := NewInput(addr1, eth123)
@@ -112,7 +110,7 @@ func TestSendMsgValidation(t *testing.T) {
 	output3 := NewOutput(addr2, eth123)
 	outputMulti := NewOutput(addr2, atom123eth123)
 
-	var emptyAddr crypto.Address
+	var emptyAddr sdk.Address
 
 	cases := []struct {
 		valid bool
@@ -168,13 +166,13 @@ func TestSendMsgValidation(t *testing.T) {
 /*
 // TODO where does this test belong ?
 func TestSendMsgSigners(t *testing.T) {
-	signers := []crypto.Address{
+	signers := []sdk.Address{
 		{1, 2, 3},
 		{4, 5, 6},
 		{7, 8, 9},
 	}
 
-	someCoins := sdk.Coins{{"atom", 123}}
+	someCoins := sdk.Coins{sdk.NewCoin("atom", 123)}
 	inputs := make([]Input, len(signers))
 	for i, signer := range signers {
 		inputs[i] = NewInput(signer, someCoins)
@@ -184,3 +182,18 @@ func TestSendMsgSigners(t *testing.T) {
 	assert.Equal(t, signers, tx.Signers())
 }
 */
+
+func TestSendMsgGetSignBytes(t *testing.T) {
+	addr1 := sdk.Address([]byte("input"))
+	addr2 := sdk.Address([]byte("output"))
+	coins := sdk.Coins{sdk.NewCoin("atom", 10)}
+	var msg = NewSendMsg(
+		[]Input{NewInput(addr1, coins)},
+		[]Output{NewOutput(addr2, coins)},
+	)
+	res := msg.GetSignBytes()
+
+	// Keys are sorted and there is no whitespace.
+	expected := `{"inputs":[{"address":"cosmosaccaddr1d9h8qat5e4ehc5","coins":[{"amount":"10","denom":"atom"}],"sequence":0}],"outputs":[{"address":"cosmosaccaddr1da6hgur4wse3jx32","coins":[{"amount":"10","denom":"atom"}]}]}`
+	assert.Equal(t, expected, string(res))
+}